@@ -0,0 +1,145 @@
+package random
+
+import "math"
+
+// An AliasTable picks indices with probability proportional to a list of weights, in O(1) time per pick,
+// using Vose's alias method (see https://www.keithschwarz.com/darts-dice-coins/ ).
+type AliasTable struct {
+	// prob[i] is the probability of picking i once i has been picked as a column, and alias[i] is the index
+	// to pick otherwise.
+	prob  []float64
+	alias []uint32
+	// weights is a copy of the weights, and sum is their sum, which are kept so that Update() can rebuild the
+	// table.
+	weights []float64
+	sum     float64
+	// scaled, small, and large are scratch space for build(), which are kept so that Update() doesn't have to
+	// allocate.
+	scaled       []float64
+	small, large []uint32
+}
+
+// NewAliasTable returns a new AliasTable for the given weights, which must be non-empty, finite, non-negative,
+// and not all zero. There must be at most 2³²-1 weights. weights is copied, so it can be modified afterwards.
+func NewAliasTable(weights []float64) *AliasTable {
+	if len(weights) == 0 {
+		panic("weights must be non-empty in call to NewAliasTable")
+	}
+
+	if uint64(len(weights)) > math.MaxUint32 {
+		panic("too many weights in call to NewAliasTable")
+	}
+
+	var sum float64
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("weights must be finite and non-negative in call to NewAliasTable")
+		}
+		sum += w
+	}
+
+	if sum == 0 {
+		panic("weights must not all be zero in call to NewAliasTable")
+	}
+
+	n := len(weights)
+	t := &AliasTable{
+		prob:    make([]float64, n),
+		alias:   make([]uint32, n),
+		weights: append([]float64(nil), weights...),
+		sum:     sum,
+		scaled:  make([]float64, n),
+		small:   make([]uint32, 0, n),
+		large:   make([]uint32, 0, n),
+	}
+	t.build()
+	return t
+}
+
+// build fills in t.prob and t.alias from t.weights and t.sum, without allocating.
+func (t *AliasTable) build() {
+	// Scale the weights so that they average to 1, and split them into the ones less than 1 and the rest.
+	n := len(t.weights)
+	scaled := t.scaled
+	small, large := t.small[:0], t.large[:0]
+	for i, w := range t.weights {
+		scaled[i] = w * float64(n) / t.sum
+		if scaled[i] < 1 {
+			small = append(small, uint32(i))
+		} else {
+			large = append(large, uint32(i))
+		}
+	}
+
+	// Fill each small column up to 1 with part of a large column.
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		t.prob[l] = scaled[l]
+		t.alias[l] = g
+		scaled[g] = (scaled[g] + scaled[l]) - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+
+	// Whatever's left should have scaled weight 1, up to rounding error.
+	for _, g := range large {
+		t.prob[g] = 1
+	}
+	for _, l := range small {
+		t.prob[l] = 1
+	}
+}
+
+// Update sets the weight at index to newWeight, which must be finite and non-negative, and rebuilds t in place.
+// index must be in the range 0 to t.Len()-1 (inclusive), and the weights must not all be zero afterwards. If
+// Update panics, t is left unchanged.
+//
+// This takes O(n) time, like NewAliasTable(), but doesn't allocate, since it reuses t's slices and scratch
+// space.
+func (t *AliasTable) Update(index int, newWeight float64) {
+	if index < 0 || index >= len(t.weights) {
+		panic("index must be in [0, Len()) in call to AliasTable.Update")
+	}
+	if !(newWeight >= 0) || math.IsInf(newWeight, 1) {
+		panic("newWeight must be finite and non-negative in call to AliasTable.Update")
+	}
+
+	// Recompute the sum from scratch instead of adjusting it, so that rounding errors don't accumulate over
+	// many updates.
+	var sum float64
+	for i, w := range t.weights {
+		if i == index {
+			w = newWeight
+		}
+		sum += w
+	}
+	if sum == 0 {
+		panic("weights must not all be zero in call to AliasTable.Update")
+	}
+
+	t.weights[index] = newWeight
+	t.sum = sum
+	t.build()
+}
+
+// Len returns the number of weights t was constructed with.
+func (t *AliasTable) Len() int {
+	return len(t.prob)
+}
+
+// Next returns an index in the range 0 to t.Len()-1 (inclusive), with probability proportional to its weight.
+// This uses one call to Uint32n() and one call to Float64().
+func (t *AliasTable) Next(src Source) int {
+	i := Uint32n(src, uint32(len(t.prob)))
+	if Float64(src) < t.prob[i] {
+		return int(i)
+	}
+	return int(t.alias[i])
+}
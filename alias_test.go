@@ -0,0 +1,180 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testAliasTableFrequencies checks that the empirical frequencies of t.Next() over many draws are close to the
+// normalized weights.
+func testAliasTableFrequencies(t *testing.T, seed int64, table *AliasTable, weights []float64) {
+	const trials = 100000
+	src := rand.NewSource(seed)
+	counts := make([]int, len(weights))
+	for i := 0; i < trials; i++ {
+		counts[table.Next(src)]++
+	}
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, weights[i]/sum, count, "weights=%v i=%d", weights, i)
+	}
+}
+
+// TestAliasTableFrequencies checks that the empirical frequencies of AliasTable.Next() converge to the
+// normalized weights.
+func TestAliasTableFrequencies(t *testing.T) {
+	t.Parallel()
+	weightsList := [][]float64{
+		{1},
+		{1, 2, 3},
+		{0, 1, 0, 1},
+		{0.1, 10, 100, 1000},
+		{1, 1, 1, 1, 1, 1, 1},
+		{5, 0, 0, 0, 0, 0, 0, 0, 0, 1e-3},
+	}
+	for i, weights := range weightsList {
+		table := NewAliasTable(weights)
+		require.Equal(t, len(weights), table.Len())
+		testAliasTableFrequencies(t, int64(i), table, weights)
+	}
+}
+
+// TestAliasTableZeroWeights checks that AliasTable.Next() never returns an index with zero weight.
+func TestAliasTableZeroWeights(t *testing.T) {
+	t.Parallel()
+	table := NewAliasTable([]float64{0, 3, 0, 0, 1, 0})
+	src := rand.NewSource(1)
+	for i := 0; i < 10000; i++ {
+		r := table.Next(src)
+		require.True(t, r == 1 || r == 4, "r=%d", r)
+	}
+}
+
+// TestAliasTableInvalid checks that NewAliasTable() panics for invalid weights.
+func TestAliasTableInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		weights []float64
+		message string
+	}{
+		{nil, "weights must be non-empty in call to NewAliasTable"},
+		{[]float64{1, -1}, "weights must be finite and non-negative in call to NewAliasTable"},
+		{[]float64{1, math.NaN()}, "weights must be finite and non-negative in call to NewAliasTable"},
+		{[]float64{1, math.Inf(1)}, "weights must be finite and non-negative in call to NewAliasTable"},
+		{[]float64{0, 0}, "weights must not all be zero in call to NewAliasTable"},
+	}
+	for _, test := range tests {
+		require.PanicsWithValue(t, test.message, func() {
+			NewAliasTable(test.weights)
+		}, "weights=%v", test.weights)
+	}
+}
+
+// TestAliasTableUpdate checks that the frequencies of AliasTable.Next() reflect the new weights after each of a
+// series of calls to Update(), and that the table isn't affected by changes to the slice it was built from.
+func TestAliasTableUpdate(t *testing.T) {
+	t.Parallel()
+	weights := []float64{1, 2, 3, 4}
+	table := NewAliasTable(weights)
+	weights[0] = 100
+	testAliasTableFrequencies(t, 1, table, []float64{1, 2, 3, 4})
+
+	updates := []struct {
+		index  int
+		weight float64
+	}{
+		{0, 10},
+		{3, 0},
+		{1, 0.5},
+		{3, 7},
+		{2, 0},
+		{0, 0},
+	}
+	expected := []float64{1, 2, 3, 4}
+	for i, u := range updates {
+		table.Update(u.index, u.weight)
+		expected[u.index] = u.weight
+		testAliasTableFrequencies(t, int64(i+2), table, expected)
+	}
+}
+
+// TestAliasTableUpdateMatchesNew checks that an updated AliasTable is the same as a new one built from the
+// updated weights.
+func TestAliasTableUpdateMatchesNew(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	weights := make([]float64, 20)
+	for i := range weights {
+		weights[i] = Float64(src)
+	}
+	table := NewAliasTable(weights)
+	for i := 0; i < 100; i++ {
+		index := int(Uint32n(src, uint32(len(weights))))
+		weights[index] = Float64(src) * 10
+		table.Update(index, weights[index])
+		expected := NewAliasTable(weights)
+		require.Equal(t, expected.prob, table.prob, "i=%d", i)
+	}
+}
+
+// TestAliasTableUpdateNoAllocs checks that AliasTable.Update() doesn't allocate.
+func TestAliasTableUpdateNoAllocs(t *testing.T) {
+	table := NewAliasTable([]float64{1, 2, 3, 4, 5})
+	w := 0.0
+	allocs := testing.AllocsPerRun(100, func() {
+		w++
+		table.Update(2, w)
+	})
+	require.Equal(t, 0.0, allocs)
+}
+
+// TestAliasTableUpdateInvalid checks that AliasTable.Update() panics for invalid arguments, and leaves the
+// table unchanged.
+func TestAliasTableUpdateInvalid(t *testing.T) {
+	t.Parallel()
+	table := NewAliasTable([]float64{0, 1, 0})
+	for _, index := range []int{-1, 3} {
+		require.PanicsWithValue(t, "index must be in [0, Len()) in call to AliasTable.Update", func() {
+			table.Update(index, 1)
+		}, "index=%d", index)
+	}
+	for _, w := range []float64{-1, math.NaN(), math.Inf(1)} {
+		require.PanicsWithValue(t, "newWeight must be finite and non-negative in call to AliasTable.Update",
+			func() {
+				table.Update(0, w)
+			}, "w=%v", w)
+	}
+	require.PanicsWithValue(t, "weights must not all be zero in call to AliasTable.Update", func() {
+		table.Update(1, 0)
+	})
+	testAliasTableFrequencies(t, 1, table, []float64{0, 1, 0})
+}
+
+var aliasTableResult int
+
+// BenchmarkAliasTableNext benchmarks AliasTable.Next() for various numbers of weights, to show that the time
+// per pick doesn't depend on the number of weights.
+func BenchmarkAliasTableNext(b *testing.B) {
+	for _, n := range []int{10, 1000, 100000} {
+		weights := make([]float64, n)
+		for i := range weights {
+			weights[i] = float64(i + 1)
+		}
+		table := NewAliasTable(weights)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			src := rand.NewSource(11)
+			var result int
+			for i := 0; i < b.N; i++ {
+				result += table.Next(src)
+			}
+			aliasTableResult = result
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package random
+
+import "math"
+
+// BenfordDigit returns a pseudo-random leading digit from 1 to 9 (inclusive) following Benford's law, i.e. d is
+// returned with probability log10(1 + 1/d), so 1 comes up about 30.1% of the time and 9 about 4.6% of the
+// time. This is useful for generating realistic-looking synthetic data.
+//
+// This uses inverse transform sampling with a single call to Float64(): the cumulative distribution function
+// is log10(d+1), so for u = Float64(src), the result is floor(10^u).
+func BenfordDigit(src Source) int {
+	d := int(math.Pow(10, Float64(src)))
+	// Guard against 10^u rounding up to 10 for u close to 1.
+	if d > 9 {
+		return 9
+	}
+	return d
+}
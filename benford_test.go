@@ -0,0 +1,38 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBenfordDigit checks that the frequency of each digit returned by BenfordDigit() is about log10(1 + 1/d),
+// i.e. about 0.301 for 1 and 0.046 for 9.
+func TestBenfordDigit(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	src := rand.NewSource(1)
+	var counts [10]int
+	for i := 0; i < trials; i++ {
+		d := BenfordDigit(src)
+		require.True(t, d >= 1 && d <= 9, "d=%d", d)
+		counts[d]++
+	}
+	require.InDelta(t, 0.301, math.Log10(2), 1e-3)
+	require.InDelta(t, 0.046, math.Log10(10.0/9), 1e-3)
+	for d := 1; d <= 9; d++ {
+		requireBinomialCount(t, trials, math.Log10(1+1/float64(d)), counts[d], "d=%d", d)
+	}
+}
+
+// TestBenfordDigitBoundaries checks that BenfordDigit() returns 1 and 9 for the smallest and largest values
+// of Float64().
+func TestBenfordDigitBoundaries(t *testing.T) {
+	t.Parallel()
+	src := makeTestSource(0, 0)
+	require.Equal(t, 1, BenfordDigit(&src))
+	maxSrc := int63Source{vs: []int64{math.MaxInt64}}
+	require.Equal(t, 9, BenfordDigit(&maxSrc))
+}
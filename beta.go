@@ -0,0 +1,30 @@
+package random
+
+import "math"
+
+// Beta returns a beta-distributed pseudo-random float64 value with shape parameters a and b, which must both
+// be positive. The result is always in the range 0.0 to 1.0 (both exclusive).
+//
+// This uses the standard method of computing X/(X+Y), where X and Y are gamma-distributed with shapes a and b,
+// but in log space, i.e. as 1/(1+exp(log Y - log X)), so that small shapes (where X and Y can underflow to 0)
+// don't cause 0/0. The result can still round to 0 or 1 if one of X and Y is much larger than the other, so
+// in that case the smallest positive float64 or the largest float64 less than 1 is returned instead.
+func Beta(src Source, a, b float64) float64 {
+	if !(a > 0) || math.IsInf(a, 1) {
+		panic("a must be positive and finite in call to Beta")
+	}
+	if !(b > 0) || math.IsInf(b, 1) {
+		panic("b must be positive and finite in call to Beta")
+	}
+
+	logX := logGammaFloat64(src, a)
+	logY := logGammaFloat64(src, b)
+	x := 1 / (1 + math.Exp(logY-logX))
+	if x <= 0 {
+		return math.SmallestNonzeroFloat64
+	}
+	if x >= 1 {
+		return math.Nextafter(1, 0)
+	}
+	return x
+}
@@ -0,0 +1,74 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBetaMoments checks that the sample mean and variance of Beta() are about a/(a+b) and
+// ab/((a+b)²(a+b+1)).
+func TestBetaMoments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct{ a, b float64 }{
+		{1, 1},
+		{0.5, 0.5},
+		{2, 5},
+		{0.2, 3},
+		{100, 10},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("a=%v,b=%v", tc.a, tc.b), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(tc.a*100 + tc.b))
+			mean, variance := sampleMoments(trials, func() float64 {
+				x := Beta(src, tc.a, tc.b)
+				require.True(t, x > 0 && x < 1, "x=%v", x)
+				return x
+			})
+			s := tc.a + tc.b
+			expectedMean := tc.a / s
+			expectedVariance := tc.a * tc.b / (s * s * (s + 1))
+			require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials))
+			// Since Beta() is bounded in (0, 1), the standard error of the variance is at most
+			// about sqrt(variance/trials).
+			require.InDelta(t, expectedVariance, variance, 5*math.Sqrt(expectedVariance/trials))
+		})
+	}
+}
+
+// TestBetaExtreme checks that Beta() stays strictly inside (0, 1) for very small and lopsided shapes.
+func TestBetaExtreme(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	testCases := []struct{ a, b float64 }{
+		{1e-3, 1e-3},
+		{1e-3, 1e3},
+		{1e3, 1e-3},
+	}
+	for _, tc := range testCases {
+		for i := 0; i < 1000; i++ {
+			x := Beta(src, tc.a, tc.b)
+			require.True(t, x > 0 && x < 1, "a=%v b=%v x=%v", tc.a, tc.b, x)
+		}
+	}
+}
+
+// TestBetaInvalid checks that Beta() panics for non-positive, infinite, or NaN shapes.
+func TestBetaInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, v := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		require.PanicsWithValue(t, "a must be positive and finite in call to Beta", func() {
+			Beta(&src, v, 1)
+		}, "a=%v", v)
+		require.PanicsWithValue(t, "b must be positive and finite in call to Beta", func() {
+			Beta(&src, 1, v)
+		}, "b=%v", v)
+	}
+}
@@ -0,0 +1,183 @@
+package random
+
+import "math"
+
+// binomialBTPEThreshold is the value of n*min(p, 1-p) above which Binomial() switches from the inversion
+// method to the BTPE method.
+const binomialBTPEThreshold = 30
+
+// Binomial returns a binomially-distributed pseudo-random int, i.e. the number of successes in n independent
+// trials that each succeed with probability p. n must be non-negative, and p must be in [0, 1].
+//
+// Let r = min(p, 1-p); values for p > 0.5 are computed by sampling with r and subtracting the result from n.
+// For n*r <= 30, this uses the inversion method, i.e. it walks the cumulative distribution function from 0
+// until it passes a uniform value, which takes O(n*r) time. For larger n*r, this uses the BTPE algorithm from
+// Kachitvichyanukul and Schmeiser's "Binomial random variate generation" (1988), which takes a small
+// constant expected number of calls to Float64().
+//
+// For n == 0, p == 0, or p == 1, this returns 0, 0, or n without using any randomness.
+func Binomial(src Source, n int, p float64) int {
+	if n < 0 {
+		panic("n must be non-negative in call to Binomial")
+	}
+	if !(p >= 0 && p <= 1) {
+		panic("p must be in [0, 1] in call to Binomial")
+	}
+
+	if n == 0 || p == 0 {
+		return 0
+	}
+	if p == 1 {
+		return n
+	}
+
+	r := math.Min(p, 1-p)
+	var y int
+	if float64(n)*r <= binomialBTPEThreshold {
+		y = binomialInversion(src, n, r)
+	} else {
+		y = binomialBTPE(src, n, r)
+	}
+
+	if p > 0.5 {
+		return n - y
+	}
+	return y
+}
+
+// binomialInversion assumes that 0 < p <= 0.5.
+func binomialInversion(src Source, n int, p float64) int {
+	q := 1 - p
+	qn := math.Exp(float64(n) * math.Log1p(-p))
+	np := float64(n) * p
+	// Restart if x gets too far out in the tail, to guard against accumulated round-off in px.
+	bound := math.Min(float64(n), np+10*math.Sqrt(np*q+1))
+
+	x := 0
+	px := qn
+	u := Float64(src)
+	for u > px {
+		x++
+		if float64(x) > bound {
+			x = 0
+			px = qn
+			u = Float64(src)
+		} else {
+			u -= px
+			px = (float64(n-x+1) * p * px) / (float64(x) * q)
+		}
+	}
+	return x
+}
+
+// binomialBTPE assumes that 0 < p <= 0.5 and n*p > binomialBTPEThreshold. The step numbers refer to the
+// paper.
+func binomialBTPE(src Source, n int, p float64) int {
+	// Step 0: Set up the triangle, parallelograms, and exponential tails of the majorizing function.
+	nf := float64(n)
+	q := 1 - p
+	npq := nf * p * q
+	fm := nf*p + p
+	m := math.Floor(fm)
+	p1 := math.Floor(2.195*math.Sqrt(npq)-4.6*q) + 0.5
+	xm := m + 0.5
+	xl := xm - p1
+	xr := xm + p1
+	c := 0.134 + 20.5/(15.3+m)
+	a := (fm - xl) / (fm - xl*p)
+	lambdaL := a * (1 + a/2)
+	a = (xr - fm) / (xr * q)
+	lambdaR := a * (1 + a/2)
+	p2 := p1 * (1 + 2*c)
+	p3 := p2 + c/lambdaL
+	p4 := p3 + c/lambdaR
+
+	for {
+		// Step 1: Sample from the triangular region, which is always accepted.
+		u := Float64(src) * p4
+		v := Float64(src)
+		var y float64
+		if u <= p1 {
+			return int(math.Floor(xm - p1*v + u))
+		}
+
+		if u <= p2 {
+			// Step 2: Sample from the parallelograms.
+			x := xl + (u-p1)/c
+			v = v*c + 1 - math.Abs(m-x+0.5)/p1
+			if v > 1 {
+				continue
+			}
+			y = math.Floor(x)
+		} else if u <= p3 {
+			// Step 3: Sample from the left exponential tail.
+			y = math.Floor(xl + math.Log(v)/lambdaL)
+			if y < 0 {
+				continue
+			}
+			v = v * (u - p2) * lambdaL
+		} else {
+			// Step 4: Sample from the right exponential tail.
+			y = math.Floor(xr - math.Log(v)/lambdaR)
+			if y > nf {
+				continue
+			}
+			v = v * (u - p3) * lambdaR
+		}
+
+		// Step 5.0: Decide whether to evaluate f(y) explicitly or to use the squeeze.
+		k := math.Abs(y - m)
+		if k <= 20 || k >= npq/2-1 {
+			// Step 5.1: Evaluate f(y)/f(m) recursively.
+			s := p / q
+			a := s * (nf + 1)
+			f := 1.0
+			if m < y {
+				for i := m + 1; i <= y; i++ {
+					f *= a/i - s
+				}
+			} else if m > y {
+				for i := y + 1; i <= m; i++ {
+					f /= a/i - s
+				}
+			}
+			if v <= f {
+				return int(y)
+			}
+			continue
+		}
+
+		// Step 5.2: Squeeze using upper and lower bounds on log(f(y)/f(m)).
+		rho := (k / npq) * ((k*(k/3+0.625)+1.0/6)/npq + 0.5)
+		t := -k * k / (2 * npq)
+		logV := math.Log(v)
+		if logV < t-rho {
+			return int(y)
+		}
+		if logV > t+rho {
+			continue
+		}
+
+		// Step 5.3: Compare against log(f(y)/f(m)) using Stirling's formula. The paper adds all four
+		// correction terms, but the ones for y! and (n-y)! should be subtracted.
+		x1 := y + 1
+		f1 := m + 1
+		z := nf + 1 - m
+		w := nf - y + 1
+		bound := xm*math.Log(f1/x1) +
+			(nf-m+0.5)*math.Log(z/w) +
+			(y-m)*math.Log(w*p/(x1*q)) +
+			stirlingCorrection(f1) + stirlingCorrection(z) -
+			stirlingCorrection(x1) - stirlingCorrection(w)
+		if logV <= bound {
+			return int(y)
+		}
+	}
+}
+
+// stirlingCorrection returns the first few correction terms of Stirling's series for log((x-1)!), i.e.
+// 1/(12x) - 1/(360x³) + 1/(1260x⁵) - 1/(1680x⁷).
+func stirlingCorrection(x float64) float64 {
+	x2 := x * x
+	return (13860 - (462-(132-(99-140/x2)/x2)/x2)/x2) / x / 166320
+}
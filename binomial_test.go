@@ -0,0 +1,104 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBinomialMoments checks that the sample mean and variance of Binomial() are about n*p and n*p*(1-p), for
+// values of n and p on both sides of binomialBTPEThreshold.
+func TestBinomialMoments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct {
+		n int
+		p float64
+	}{
+		{1, 0.5},
+		{10, 0.1},
+		{100, 0.25},
+		{60, 0.5},
+		{61, 0.5},
+		{100, 0.9},
+		{1000, 0.3},
+		{1000000, 0.5},
+		{1000000, 0.999},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("n=%d,p=%v", tc.n, tc.p), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(tc.n) + int64(tc.p*1000))
+			mean, variance := sampleMoments(trials, func() float64 {
+				return float64(Binomial(src, tc.n, tc.p))
+			})
+			n := float64(tc.n)
+			expectedMean := n * tc.p
+			expectedVariance := n * tc.p * (1 - tc.p)
+			// The standard error of the mean is sqrt(npq/trials), and the standard error of the
+			// variance is at most about npq*sqrt(3/trials).
+			require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials))
+			require.InDelta(t, expectedVariance, variance, 5*expectedVariance*math.Sqrt(3.0/trials))
+		})
+	}
+}
+
+// TestBinomialProbabilities checks that the fraction of Binomial() values equal to k near the mode is about
+// right, for values of n and p on both sides of binomialBTPEThreshold.
+func TestBinomialProbabilities(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct {
+		n int
+		p float64
+	}{
+		{20, 0.3},
+		{200, 0.4},
+		{200, 0.8},
+	}
+	for _, tc := range testCases {
+		src := rand.NewSource(int64(tc.n))
+		counts := make(map[int]int)
+		for i := 0; i < trials; i++ {
+			counts[Binomial(src, tc.n, tc.p)]++
+		}
+		mode := int(float64(tc.n) * tc.p)
+		for k := mode - 2; k <= mode+2; k++ {
+			lgammaN1, _ := math.Lgamma(float64(tc.n + 1))
+			lgammaK1, _ := math.Lgamma(float64(k + 1))
+			lgammaNK1, _ := math.Lgamma(float64(tc.n - k + 1))
+			p := math.Exp(lgammaN1 - lgammaK1 - lgammaNK1 +
+				float64(k)*math.Log(tc.p) + float64(tc.n-k)*math.Log(1-tc.p))
+			requireBinomialCount(t, trials, p, counts[k], "n=%d p=%v k=%d", tc.n, tc.p, k)
+		}
+	}
+}
+
+// TestBinomialTrivial checks that Binomial() returns 0 or n without using any randomness when n == 0, p == 0,
+// or p == 1.
+func TestBinomialTrivial(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, 0, Binomial(&src, 0, 0.5))
+	require.Equal(t, 0, Binomial(&src, 10, 0))
+	require.Equal(t, 10, Binomial(&src, 10, 1))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestBinomialInvalid checks that Binomial() panics for negative n or p outside of [0, 1].
+func TestBinomialInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-negative in call to Binomial", func() {
+		Binomial(&src, -1, 0.5)
+	})
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		require.PanicsWithValue(t, "p must be in [0, 1] in call to Binomial", func() {
+			Binomial(&src, 10, p)
+		}, "p=%v", p)
+	}
+}
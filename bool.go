@@ -0,0 +1,23 @@
+package random
+
+// Bool returns true or false with equal probability, using a single bit (the top one) of one call to
+// src.Int63().
+func Bool(src Source) bool {
+	return src.Int63()>>62 != 0
+}
+
+// BoolP returns true with probability p, and false otherwise. p must be in the range 0.0 to 1.0 (inclusive).
+// If p is exactly 0.0 or 1.0, no randomness is used.
+func BoolP(src Source, p float64) bool {
+	if !(p >= 0 && p <= 1) {
+		panic("p must be in [0, 1] in call to BoolP")
+	}
+
+	switch p {
+	case 0:
+		return false
+	case 1:
+		return true
+	}
+	return Float64(src) < p
+}
@@ -0,0 +1,70 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBoolTopBit checks that Bool() uses the top bit of a single call to Int63().
+func TestBoolTopBit(t *testing.T) {
+	t.Parallel()
+	src := int63Source{vs: []int64{0, 1<<62 - 1, 1 << 62, math.MaxInt64}}
+	require.False(t, Bool(&src))
+	require.False(t, Bool(&src))
+	require.True(t, Bool(&src))
+	require.True(t, Bool(&src))
+	require.Equal(t, 4, src.callCount)
+}
+
+// TestBoolUniform checks that Bool() returns true about half the time.
+func TestBoolUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	src := rand.NewSource(1)
+	count := 0
+	for i := 0; i < trials; i++ {
+		if Bool(src) {
+			count++
+		}
+	}
+	requireBinomialCount(t, trials, 0.5, count)
+}
+
+// TestBoolPFrequency checks that BoolP() returns true with about the right frequency.
+func TestBoolPFrequency(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	for _, p := range []float64{0.001, 0.1, 0.5, 0.75, 0.999} {
+		src := rand.NewSource(int64(p * 1000))
+		count := 0
+		for i := 0; i < trials; i++ {
+			if BoolP(src, p) {
+				count++
+			}
+		}
+		requireBinomialCount(t, trials, p, count, "p=%v", p)
+	}
+}
+
+// TestBoolPCertain checks that BoolP() doesn't use any randomness for p == 0 or p == 1.
+func TestBoolPCertain(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.False(t, BoolP(&src, 0))
+	require.True(t, BoolP(&src, 1))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestBoolPInvalid checks that BoolP() panics for p outside [0, 1].
+func TestBoolPInvalid(t *testing.T) {
+	t.Parallel()
+	for _, p := range []float64{-0.1, 1.1, math.NaN(), math.Inf(1)} {
+		src := testSource{}
+		require.PanicsWithValue(t, "p must be in [0, 1] in call to BoolP", func() {
+			BoolP(&src, p)
+		}, "p=%v", p)
+	}
+}
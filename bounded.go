@@ -0,0 +1,136 @@
+package random
+
+import "sync"
+
+// Threshold returns 2³² % n, which is the threshold that Uint32n() uses to decide whether to reject a value:
+// for a uniformly-distributed uint32 v, the value (v*n)>>32 is accepted if and only if the low 32 bits of
+// v*n are at least Threshold(n), so the rejection probability is Threshold(n)/2³². n must be non-zero. If n is
+// a power of two, then Threshold(n) is 0, i.e. nothing is rejected.
+//
+// This is computed as -n % n; see the comments in Uint32nErr() for why.
+func Threshold(n uint32) uint32 {
+	if n == 0 {
+		panic("n must be non-zero in call to Threshold")
+	}
+
+	return -n % n
+}
+
+// RejectionProbability returns the probability that Uint32n(src, n) rejects a single uniformly-distributed
+// uint32 value, i.e. Threshold(n)/2³². The average number of values that Uint32n() draws per call is then
+// 1/(1 - RejectionProbability(n)). n must be non-zero. This is 0 if n is a power of two, and is always less
+// than 1/2.
+func RejectionProbability(n uint32) float64 {
+	if n == 0 {
+		panic("n must be non-zero in call to RejectionProbability")
+	}
+
+	return float64(Threshold(n)) / (1 << 32)
+}
+
+// A Bounded32 generates uniformly-distributed numbers in the range 0 to n-1 (inclusive) for a fixed n, with
+// the threshold computation from Uint32n() done once up front.
+//
+// Note that this gives no speedup over Uint32n(): Uint32n() only computes the threshold when the low 32 bits
+// of its product are less than n, which is rare unless n is huge, so precomputing it saves almost nothing.
+// Bounded32 is still handy for passing a fixed range around along with a way to draw from it.
+type Bounded32 struct {
+	n uint32
+	// threshold is 2³² % n; see the comments for Uint32n().
+	threshold uint32
+}
+
+// NewBounded32 returns a Bounded32 for the given n, which must be non-zero.
+func NewBounded32(n uint32) Bounded32 {
+	if n == 0 {
+		panic("n must be non-zero in call to NewBounded32")
+	}
+
+	return Bounded32{n: n, threshold: Threshold(n)}
+}
+
+// N returns the n that b was constructed with.
+func (b Bounded32) N() uint32 {
+	return b.n
+}
+
+// Next returns a uniformly-distributed number in the range 0 to b.N()-1 (inclusive). This returns the same
+// value as Uint32n(src, b.N()) would, and consumes the same number of values from src.
+func (b Bounded32) Next(src Source) uint32 {
+	// Since the threshold is already computed, this is just the loop from the comments for Uint32n() (***).
+	// If b.N() is a power of two, then the threshold is 0, so nothing is rejected.
+	for {
+		prod := uint64(randUint32(src)) * uint64(b.n)
+		if uint32(prod) >= b.threshold {
+			return uint32(prod >> 32)
+		}
+	}
+}
+
+// Uint32nBatch fills out with independent uniformly-distributed numbers in the range 0 to n-1 (inclusive).
+// n must be non-zero. The values are the same as the ones that would be returned by calling Uint32n(src, n)
+// len(out) times.
+//
+// Like Bounded32, the threshold is computed at most once for the whole batch, but like Uint32n(), the
+// threshold is only computed if it's needed, and it's not needed at all if n is a power of two.
+func Uint32nBatch(src Source, n uint32, out []uint32) {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint32nBatch")
+	}
+
+	// If n is a power of two, then the threshold is 0. Otherwise, it's computed when it's first needed.
+	haveThreshold := n&(n-1) == 0
+	var threshold uint32
+	for i := range out {
+		prod := uint64(randUint32(src)) * uint64(n)
+		low := uint32(prod)
+		if low < n {
+			if !haveThreshold {
+				threshold = -n % n
+				haveThreshold = true
+			}
+			for low < threshold {
+				prod = uint64(randUint32(src)) * uint64(n)
+				low = uint32(prod)
+			}
+		}
+		out[i] = uint32(prod >> 32)
+	}
+}
+
+// Uint32nBatchParallel fills out with independent uniformly-distributed numbers in the range 0 to n-1
+// (inclusive), like Uint32nBatch(), but splits out into len(srcs) chunks of nearly equal size and fills each
+// one from its own Source on its own goroutine, and waits for them all to finish. n must be non-zero, and srcs
+// must be non-empty.
+//
+// The ith chunk is filled by Uint32nBatch(srcs[i], n, chunk), so the result is deterministic for fixed states
+// of the Sources, regardless of scheduling. The Sources must be distinct, since each one is used by a
+// different goroutine without locking, and they should be independent, e.g. from SplitStreams() or
+// DeriveSource(), since otherwise the chunks may be correlated.
+func Uint32nBatchParallel(srcs []Source, n uint32, out []uint32) {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint32nBatchParallel")
+	}
+	if len(srcs) == 0 {
+		panic("srcs must be non-empty in call to Uint32nBatchParallel")
+	}
+
+	// The first len(out) % len(srcs) chunks get one extra element. This avoids computing i*len(out), which
+	// could overflow.
+	size, extra := len(out)/len(srcs), len(out)%len(srcs)
+	var wg sync.WaitGroup
+	start := 0
+	for i, src := range srcs {
+		end := start + size
+		if i < extra {
+			end++
+		}
+		wg.Add(1)
+		go func(src Source, chunk []uint32) {
+			defer wg.Done()
+			Uint32nBatch(src, n, chunk)
+		}(src, out[start:end])
+		start = end
+	}
+	wg.Wait()
+}
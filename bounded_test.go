@@ -0,0 +1,329 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBounded32MatchesUint32n checks that Bounded32.Next() returns the same values as Uint32n() for
+// various values of n, including powers of two.
+func TestBounded32MatchesUint32n(t *testing.T) {
+	t.Parallel()
+	ns := []uint32{1, 2, 3, 5, 6, 7, 1000, 1024, 0x7fffffff, 0x80000000, 0x80000001, 0xc0000000, 0xfffffffe, 0xffffffff}
+	for _, n := range ns {
+		b := NewBounded32(n)
+		require.Equal(t, n, b.N())
+		src := rand.NewSource(int64(n))
+		expectedSrc := rand.NewSource(int64(n))
+		for i := 0; i < 1000; i++ {
+			require.Equal(t, Uint32n(expectedSrc, n), b.Next(src), "n=%d i=%d", n, i)
+		}
+		// Check that both sources are in the same state.
+		require.Equal(t, expectedSrc.Int63(), src.Int63(), "n=%d", n)
+	}
+}
+
+// TestBounded32Rejection checks that Bounded32.Next() rejects the same values as Uint32n().
+func TestBounded32Rejection(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint32{3, 0x80000001, 0xffffffff} {
+		b := NewBounded32(n)
+		for r := 0; r < 3; r++ {
+			src := makeTestSource(r, 0xffffffff)
+			u := b.Next(&src)
+			require.Equal(t, r+1, src.callCount, "n=%d r=%d", n, r)
+			require.Equal(t, n-1, u, "n=%d r=%d", n, r)
+		}
+	}
+}
+
+// TestBounded32PowersOfTwo checks that Bounded32.Next() has a uniform distribution when n is a power of two,
+// by looping through all possible values of the high 16 bits of the input, and that no values are rejected.
+func TestBounded32PowersOfTwo(t *testing.T) {
+	t.Parallel()
+	for i := uint32(0); i <= 16; i++ {
+		n := uint32(1) << i
+		b := NewBounded32(n)
+		buckets := make([]uint32, n)
+		for v := uint32(0); v < 1<<16; v++ {
+			src := makeTestSource(0, v<<16|0xabcd)
+			u := b.Next(&src)
+			require.Equal(t, 1, src.callCount)
+			require.Less(t, u, n)
+			buckets[u]++
+		}
+		for j := uint32(0); j < n; j++ {
+			require.Equal(t, (1<<16)/n, buckets[j], "n=%d j=%d", n, j)
+		}
+	}
+}
+
+// TestBounded32Zero checks that NewBounded32() panics for n == 0.
+func TestBounded32Zero(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "n must be non-zero in call to NewBounded32", func() {
+		NewBounded32(0)
+	})
+}
+
+// The BenchmarkBounded32* functions benchmark Bounded32.Next() against Uint32n() in a hot loop with a
+// constant n, which is either a power of two or not.
+//
+// In my runs, Bounded32.Next() was no faster than Uint32n(), and sometimes slightly slower, since Uint32n()
+// rarely needs to compute the threshold anyway, and both are dominated by the call to the Source.
+
+const boundedN = 1000
+const boundedPowerOfTwoN = 1024
+
+var bounded32Result uint32
+
+func BenchmarkBounded32Next(b *testing.B) {
+	src := rand.NewSource(6)
+	bounded := NewBounded32(boundedN)
+	for n := 0; n < b.N; n++ {
+		bounded32Result += bounded.Next(src)
+	}
+}
+
+var bounded32Uint32nResult uint32
+
+func BenchmarkBounded32Uint32n(b *testing.B) {
+	src := rand.NewSource(6)
+	for n := 0; n < b.N; n++ {
+		bounded32Uint32nResult += Uint32n(src, boundedN)
+	}
+}
+
+var bounded32PowerOfTwoResult uint32
+
+func BenchmarkBounded32PowerOfTwoNext(b *testing.B) {
+	src := rand.NewSource(7)
+	bounded := NewBounded32(boundedPowerOfTwoN)
+	for n := 0; n < b.N; n++ {
+		bounded32PowerOfTwoResult += bounded.Next(src)
+	}
+}
+
+var bounded32PowerOfTwoUint32nResult uint32
+
+func BenchmarkBounded32PowerOfTwoUint32n(b *testing.B) {
+	src := rand.NewSource(7)
+	for n := 0; n < b.N; n++ {
+		bounded32PowerOfTwoUint32nResult += Uint32n(src, boundedPowerOfTwoN)
+	}
+}
+
+// TestUint32nBatchMatchesUint32n checks that Uint32nBatch() returns the same values as calling Uint32n()
+// repeatedly.
+func TestUint32nBatchMatchesUint32n(t *testing.T) {
+	t.Parallel()
+	ns := []uint32{1, 2, 3, 1000, 1024, 0x80000001, 0xc0000000, 0xffffffff}
+	for _, n := range ns {
+		src := rand.NewSource(int64(n))
+		expectedSrc := rand.NewSource(int64(n))
+		out := make([]uint32, 1000)
+		Uint32nBatch(src, n, out)
+		for i, u := range out {
+			require.Equal(t, Uint32n(expectedSrc, n), u, "n=%d i=%d", n, i)
+		}
+		require.Equal(t, expectedSrc.Int63(), src.Int63(), "n=%d", n)
+	}
+}
+
+// TestUint32nBatchRejection checks that Uint32nBatch() rejects the same values as Uint32n(), both before and
+// after the threshold is computed.
+func TestUint32nBatchRejection(t *testing.T) {
+	t.Parallel()
+	src := testSource{vs: []uint32{0xffffffff, 0, 0, 0xffffffff, 0, 0xffffffff}}
+	out := make([]uint32, 3)
+	Uint32nBatch(&src, 3, out)
+	require.Equal(t, []uint32{2, 2, 2}, out)
+	require.Equal(t, 6, src.callCount)
+}
+
+// TestUint32nBatchUniform checks that the values from Uint32nBatch() are roughly uniform.
+func TestUint32nBatchUniform(t *testing.T) {
+	t.Parallel()
+	const n = 7
+	const trials = 100000
+	src := rand.NewSource(9)
+	out := make([]uint32, trials)
+	Uint32nBatch(src, n, out)
+	var buckets [n]int
+	for _, u := range out {
+		buckets[u]++
+	}
+	for i, count := range buckets {
+		requireBinomialCount(t, trials, 1.0/n, count, "i=%d", i)
+	}
+}
+
+// TestUint32nBatchEmpty checks that Uint32nBatch() doesn't use any randomness for an empty out.
+func TestUint32nBatchEmpty(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	Uint32nBatch(&src, 3, nil)
+	Uint32nBatch(&src, 3, []uint32{})
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestUint32nBatchZero checks that Uint32nBatch() panics for n == 0.
+func TestUint32nBatchZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Uint32nBatch", func() {
+		Uint32nBatch(&src, 0, make([]uint32, 1))
+	})
+}
+
+// TestUint32nBatchParallelMatchesUint32nBatch checks that Uint32nBatchParallel() fills each chunk with the same
+// values as Uint32nBatch() on the corresponding Source, including when there are more Sources than values.
+func TestUint32nBatchParallelMatchesUint32nBatch(t *testing.T) {
+	t.Parallel()
+	for _, numSrcs := range []int{1, 3, 4, 20} {
+		srcs := SplitStreams(NewXoshiro256(1), numSrcs)
+		expectedSrcs := SplitStreams(NewXoshiro256(1), numSrcs)
+		out := make([]uint32, 10)
+		Uint32nBatchParallel(srcs, 1000, out)
+
+		var expected []uint32
+		for i, src := range expectedSrcs {
+			size := len(out) / numSrcs
+			if i < len(out)%numSrcs {
+				size++
+			}
+			chunk := make([]uint32, size)
+			Uint32nBatch(src, 1000, chunk)
+			expected = append(expected, chunk...)
+		}
+		require.Equal(t, expected, out, "numSrcs=%d", numSrcs)
+	}
+}
+
+// TestUint32nBatchParallelUniform checks that the values from Uint32nBatchParallel() are roughly uniform.
+func TestUint32nBatchParallelUniform(t *testing.T) {
+	t.Parallel()
+	const n = 7
+	const trials = 100000
+	out := make([]uint32, trials)
+	Uint32nBatchParallel(SplitStreams(NewXoshiro256(9), 8), n, out)
+	var buckets [n]int
+	for _, u := range out {
+		buckets[u]++
+	}
+	for i, count := range buckets {
+		requireBinomialCount(t, trials, 1.0/n, count, "i=%d", i)
+	}
+}
+
+// TestUint32nBatchParallelInvalid checks that Uint32nBatchParallel() panics for n == 0 or empty srcs.
+func TestUint32nBatchParallelInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Uint32nBatchParallel", func() {
+		Uint32nBatchParallel([]Source{&src}, 0, make([]uint32, 1))
+	})
+	require.PanicsWithValue(t, "srcs must be non-empty in call to Uint32nBatchParallel", func() {
+		Uint32nBatchParallel(nil, 3, make([]uint32, 1))
+	})
+}
+
+// The BenchmarkUint32nBatch* functions benchmark filling a slice using Uint32nBatch() against a naive loop
+// calling Uint32n().
+
+const batchSize = 4096
+
+var batchResult []uint32
+
+func BenchmarkUint32nBatch(b *testing.B) {
+	src := rand.NewSource(10)
+	out := make([]uint32, batchSize)
+	for n := 0; n < b.N; n++ {
+		Uint32nBatch(src, boundedN, out)
+	}
+	batchResult = out
+}
+
+var batchLoopResult []uint32
+
+func BenchmarkUint32nBatchLoop(b *testing.B) {
+	src := rand.NewSource(10)
+	out := make([]uint32, batchSize)
+	for n := 0; n < b.N; n++ {
+		for i := range out {
+			out[i] = Uint32n(src, boundedN)
+		}
+	}
+	batchLoopResult = out
+}
+
+// TestThreshold checks Threshold() against the brute-force computation of 2³² % n.
+func TestThreshold(t *testing.T) {
+	t.Parallel()
+	check := func(n uint32) {
+		require.Equal(t, uint32((1<<32)%uint64(n)), Threshold(n), "n=%d", n)
+	}
+	for n := uint32(1); n <= 10000; n++ {
+		check(n)
+	}
+	for n := uint32(math.MaxUint32); n >= math.MaxUint32-10000; n-- {
+		check(n)
+	}
+	src := rand.NewSource(1)
+	for i := 0; i < 10000; i++ {
+		check(randUint32(src) | 1)
+	}
+	for i := 0; i < 32; i++ {
+		require.Equal(t, uint32(0), Threshold(1<<i), "i=%d", i)
+	}
+}
+
+// TestThresholdZero checks that Threshold() panics for n == 0.
+func TestThresholdZero(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "n must be non-zero in call to Threshold", func() {
+		Threshold(0)
+	})
+}
+
+// TestRejectionProbability checks RejectionProbability() against analytic values.
+func TestRejectionProbability(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 32; i++ {
+		require.Equal(t, 0.0, RejectionProbability(1<<i), "i=%d", i)
+	}
+	// 2³² = 3*1431655765 + 1.
+	require.Equal(t, 1.0/(1<<32), RejectionProbability(3))
+	// 2³² = 1*(2³¹+1) + (2³¹-1).
+	require.Equal(t, float64(1<<31-1)/(1<<32), RejectionProbability(1<<31+1))
+	// 2³² = 1*(2³²-1) + 1.
+	require.Equal(t, 1.0/(1<<32), RejectionProbability(math.MaxUint32))
+	// 2³² = 4294*1000000 + 967296.
+	require.Equal(t, 967296.0/(1<<32), RejectionProbability(1000000))
+}
+
+// TestRejectionProbabilityDraws checks that the average number of calls to Int63() per call to Uint32n() is
+// about 1/(1 - RejectionProbability(n)), for an n with a high rejection probability.
+func TestRejectionProbabilityDraws(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const n = 1<<31 + 1
+	src := NewStatsSource(rand.NewSource(1))
+	for i := 0; i < trials; i++ {
+		Uint32n(src, n)
+	}
+	p := RejectionProbability(n)
+	// The number of rejections per call is geometric, with variance p/(1-p)².
+	require.InDelta(t, 1/(1-p), float64(src.Calls())/trials, 5*math.Sqrt(p/((1-p)*(1-p))/trials))
+}
+
+// TestRejectionProbabilityZero checks that RejectionProbability() panics for n == 0.
+func TestRejectionProbabilityZero(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "n must be non-zero in call to RejectionProbability", func() {
+		RejectionProbability(0)
+	})
+}
@@ -0,0 +1,30 @@
+package random
+
+import "math"
+
+// RandomBucket returns a uniformly-distributed bucket index in the range 0 to numBuckets-1 (inclusive).
+// numBuckets must be positive.
+//
+// Unlike Intn(), this uses Uint32n() whenever numBuckets fits in 32 bits, regardless of the size of int, so it
+// returns the same buckets for the same state of src on every platform. This matters when the assignment is
+// done by many workers, e.g. in the first pass of a two-pass shuffle of data that doesn't fit in memory:
+//
+//  1. For each record, in any order, append it to bucket RandomBucket(src, numBuckets), where numBuckets is
+//     chosen so that each bucket fits in memory.
+//  2. For each bucket in order, read it into memory, shuffle it with Shuffle() or ShuffleSlice(), and append it
+//     to the output.
+//
+// The output is then a uniformly-distributed permutation of the input, as long as each record's bucket is drawn
+// independently of the others: given the number of records in each bucket, every assignment of the records to
+// positions in the output is equally likely. (This is Rao and Sandelius's method.) If the records are assigned
+// by multiple workers, each one should use its own Source, e.g. from DeriveSource() or SplitStreams().
+func RandomBucket(src Source, numBuckets int) int {
+	if numBuckets <= 0 {
+		panic("numBuckets must be positive in call to RandomBucket")
+	}
+
+	if uint64(numBuckets) <= math.MaxUint32 {
+		return int(Uint32n(src, uint32(numBuckets)))
+	}
+	return int(Uint64n(src, uint64(numBuckets)))
+}
@@ -0,0 +1,77 @@
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomBucketUniform checks that RandomBucket() returns each bucket roughly uniformly.
+func TestRandomBucketUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	for _, numBuckets := range []int{1, 2, 7, 10} {
+		src := rand.NewSource(int64(numBuckets))
+		counts := make([]int, numBuckets)
+		for i := 0; i < trials; i++ {
+			counts[RandomBucket(src, numBuckets)]++
+		}
+		for b, count := range counts {
+			requireBinomialCount(t, trials, 1/float64(numBuckets), count, "numBuckets=%d b=%d", numBuckets, b)
+		}
+	}
+}
+
+// TestRandomBucketMatchesUint32n checks that RandomBucket() returns the same values as Uint32n() for
+// numBuckets that fit in 32 bits.
+func TestRandomBucketMatchesUint32n(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	expectedSrc := rand.NewSource(1)
+	for _, numBuckets := range []int{1, 3, 1000, 1<<31 - 1} {
+		require.Equal(t, int(Uint32n(expectedSrc, uint32(numBuckets))), RandomBucket(src, numBuckets),
+			"numBuckets=%d", numBuckets)
+	}
+}
+
+// TestRandomBucketTwoPassShuffle checks that the two-pass shuffle described in the comments for RandomBucket()
+// returns each permutation roughly uniformly.
+func TestRandomBucketTwoPassShuffle(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const n = 4
+	const numBuckets = 3
+	src := rand.NewSource(2)
+	counts := make(map[string]int)
+	for trial := 0; trial < trials; trial++ {
+		buckets := make([][]int, numBuckets)
+		for i := 0; i < n; i++ {
+			b := RandomBucket(src, numBuckets)
+			buckets[b] = append(buckets[b], i)
+		}
+		var out []int
+		for _, bucket := range buckets {
+			ShuffleSlice(src, bucket)
+			out = append(out, bucket...)
+		}
+		counts[fmt.Sprint(out)]++
+	}
+	// There are 4! = 24 permutations.
+	require.Equal(t, 24, len(counts))
+	for perm, count := range counts {
+		requireBinomialCount(t, trials, 1.0/24, count, "perm=%s", perm)
+	}
+}
+
+// TestRandomBucketInvalid checks that RandomBucket() panics for non-positive numBuckets.
+func TestRandomBucketInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, numBuckets := range []int{0, -1} {
+		require.PanicsWithValue(t, "numBuckets must be positive in call to RandomBucket", func() {
+			RandomBucket(&src, numBuckets)
+		}, "numBuckets=%d", numBuckets)
+	}
+}
@@ -0,0 +1,71 @@
+package random
+
+// A BufferedSource wraps a Source64, and serves values from an internal buffer of uint32 words that is refilled
+// from the wrapped Source64 in batches. This amortizes the overhead of sources that are expensive per call,
+// e.g. ones that lock a mutex or make a syscall. Each Uint64() value from the wrapped Source64 is split into
+// two words, with the high 32 bits first.
+//
+// Uint32n() (and the other functions that need 32-bit values) use a single word per draw, and Uint64n()
+// (and the other functions that need 64-bit values) use two consecutive words per draw. So if the buffer has
+// an even number of words, a BufferedSource yields exactly the same values as the wrapped Source64 for
+// functions that use 64-bit values, as long as the words are consumed in pairs. If it has an odd number of
+// words, then the low 32 bits of every (bufWords+1)/2th value from the wrapped Source64 are discarded, so the
+// values differ.
+//
+// A BufferedSource is not safe for concurrent use by multiple goroutines.
+type BufferedSource struct {
+	src Source64
+	buf []uint32
+	// i is the index of the next unused word in buf.
+	i int
+}
+
+// NewBufferedSource returns a new BufferedSource wrapping src with a buffer of bufWords words. bufWords must
+// be positive, and should be even for the values to match those of src; see the BufferedSource comment. The
+// buffer isn't filled until the first draw.
+func NewBufferedSource(src Source64, bufWords int) *BufferedSource {
+	if bufWords <= 0 {
+		panic("bufWords must be positive in call to NewBufferedSource")
+	}
+
+	buf := make([]uint32, bufWords)
+	return &BufferedSource{src: src, buf: buf, i: len(buf)}
+}
+
+// refill refills the buffer from the wrapped Source64. If the buffer has an odd number of words, then the last
+// word is taken from the high 32 bits of an extra value, and the low 32 bits are discarded.
+func (b *BufferedSource) refill() {
+	j := 0
+	for ; j+1 < len(b.buf); j += 2 {
+		v := b.src.Uint64()
+		b.buf[j] = uint32(v >> 32)
+		b.buf[j+1] = uint32(v)
+	}
+	if j < len(b.buf) {
+		b.buf[j] = uint32(b.src.Uint64() >> 32)
+	}
+	b.i = 0
+}
+
+// Uint32 returns the next word from the buffer, refilling it first if it's empty.
+func (b *BufferedSource) Uint32() uint32 {
+	if b.i >= len(b.buf) {
+		b.refill()
+	}
+	v := b.buf[b.i]
+	b.i++
+	return v
+}
+
+// Uint64 returns the next two words from the buffer, with the first one as the high 32 bits. The buffer may be
+// refilled between the two words.
+func (b *BufferedSource) Uint64() uint64 {
+	high := b.Uint32()
+	low := b.Uint32()
+	return uint64(high)<<32 | uint64(low)
+}
+
+// Int63 returns the top 63 bits of b.Uint64().
+func (b *BufferedSource) Int63() int64 {
+	return int64(b.Uint64() >> 1)
+}
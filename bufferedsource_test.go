@@ -0,0 +1,129 @@
+package random
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// makeWordSource returns a testSource that returns the words that a BufferedSource with an even buffer size
+// would return for rand.NewSource(seed), i.e. the high and low 32 bits of each Uint64() value.
+func makeWordSource(seed int64, wordCount int) testSource {
+	src := rand.NewSource(seed).(rand.Source64)
+	vs := make([]uint32, 0, wordCount)
+	for len(vs) < wordCount {
+		v := src.Uint64()
+		vs = append(vs, uint32(v>>32), uint32(v))
+	}
+	return testSource{vs: vs}
+}
+
+// TestBufferedSourceUint32n checks that Uint32n() on a BufferedSource uses one word per draw, even across
+// refills, by comparing against a testSource returning the same words.
+func TestBufferedSourceUint32n(t *testing.T) {
+	t.Parallel()
+	for _, bufWords := range []int{2, 4, 10, 64} {
+		src := NewBufferedSource(rand.NewSource(1).(Source64), bufWords)
+		expectedSrc := makeWordSource(1, 2000)
+		for i := 0; i < 1000; i++ {
+			n := uint32(i%7 + 1)
+			require.Equal(t, Uint32n(&expectedSrc, n), Uint32n(src, n), "bufWords=%d i=%d", bufWords, i)
+		}
+		// Check that both sources have consumed the same number of words.
+		require.Equal(t, expectedSrc.vs[expectedSrc.callCount], src.Uint32(), "bufWords=%d", bufWords)
+	}
+}
+
+// TestBufferedSourceOddSize checks that a BufferedSource with an odd buffer size takes the last word of each
+// refill from the high 32 bits of an extra value.
+func TestBufferedSourceOddSize(t *testing.T) {
+	t.Parallel()
+	src64 := testUint64Source{vs: []uint64{
+		0x0000000100000002, 0x0000000300000004,
+		0x0000000500000006, 0x0000000700000008,
+	}}
+	src := NewBufferedSource(&src64, 3)
+	for i := uint32(1); i <= 3; i++ {
+		require.Equal(t, i, src.Uint32())
+	}
+	require.Equal(t, 2, src64.callCount)
+	// The next value straddles a refill.
+	require.Equal(t, uint64(0x0000000500000006), src.Uint64())
+	require.Equal(t, 4, src64.callCount)
+	require.Equal(t, uint32(7), src.Uint32())
+}
+
+// TestBufferedSourceUint64n checks that Uint64n() on a BufferedSource with an even buffer size returns the same
+// values as Uint64n() on the wrapped Source64.
+func TestBufferedSourceUint64n(t *testing.T) {
+	t.Parallel()
+	for _, bufWords := range []int{2, 4, 10, 64} {
+		src := NewBufferedSource(rand.NewSource(2).(Source64), bufWords)
+		expectedSrc := rand.NewSource(2)
+		for i := 0; i < 1000; i++ {
+			n := uint64(i)*0x123456789 + 1
+			require.Equal(t, Uint64n(expectedSrc, n), Uint64n(src, n), "bufWords=%d i=%d", bufWords, i)
+		}
+	}
+}
+
+// TestBufferedSourceLazy checks that a BufferedSource doesn't fill its buffer until the first draw.
+func TestBufferedSourceLazy(t *testing.T) {
+	t.Parallel()
+	src64 := testUint64Source{vs: []uint64{1, 2}}
+	src := NewBufferedSource(&src64, 4)
+	require.Equal(t, 0, src64.callCount)
+	src.Uint32()
+	require.Equal(t, 2, src64.callCount)
+}
+
+// TestBufferedSourceNonPositive checks that NewBufferedSource() panics for non-positive bufWords.
+func TestBufferedSourceNonPositive(t *testing.T) {
+	t.Parallel()
+	for _, bufWords := range []int{0, -1} {
+		require.PanicsWithValue(t, "bufWords must be positive in call to NewBufferedSource", func() {
+			NewBufferedSource(rand.NewSource(1).(Source64), bufWords)
+		})
+	}
+}
+
+// lockedSource is a Source64 that locks a mutex around each call, to simulate an expensive source.
+type lockedSource struct {
+	lk  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.src.Uint64()
+}
+
+// The BenchmarkUint32n*LockedSource functions benchmark a tight Uint32n() loop on a lockedSource, with and
+// without buffering.
+
+var lockedSourceResult uint32
+
+func BenchmarkUint32nLockedSource(b *testing.B) {
+	src := &lockedSource{src: rand.NewSource(8).(rand.Source64)}
+	for n := 0; n < b.N; n++ {
+		lockedSourceResult += Uint32n(src, 1000)
+	}
+}
+
+var bufferedLockedSourceResult uint32
+
+func BenchmarkUint32nBufferedLockedSource(b *testing.B) {
+	src := NewBufferedSource(&lockedSource{src: rand.NewSource(8).(rand.Source64)}, 256)
+	for n := 0; n < b.N; n++ {
+		bufferedLockedSourceResult += Uint32n(src, 1000)
+	}
+}
@@ -0,0 +1,47 @@
+package random
+
+import "encoding/binary"
+
+// A BufferSource is a Source64 that returns values from a fixed byte buffer, which is useful for writing
+// deterministic tests against the functions in this package.
+//
+// Each value uses the next 8 bytes of the buffer, interpreted as a big-endian uint64: Uint64() returns it
+// directly, and Int63() returns its top 63 bits. So for functions that need 32-bit values, like Uint32n(), each
+// draw uses the first 4 bytes of each 8-byte chunk as a big-endian uint32 v, and the other 4 bytes are ignored.
+// For example, Uint32n(src, 3) rejects v = 0 (see the comments for Uint32n()), so the buffer
+//
+//	00 00 00 00 00 00 00 00  ff ff ff ff 00 00 00 00
+//
+// makes Uint32n(src, 3) reject the first draw and return 2 for the second one.
+//
+// A BufferSource panics if there are fewer than 8 bytes left for the next value.
+type BufferSource struct {
+	data []byte
+}
+
+// NewBufferSource returns a new BufferSource that returns values from data. data isn't copied, so it shouldn't
+// be modified while the BufferSource is in use.
+func NewBufferSource(data []byte) *BufferSource {
+	return &BufferSource{data: data}
+}
+
+// Uint64 returns the next 8 bytes of the buffer as a big-endian uint64.
+func (b *BufferSource) Uint64() uint64 {
+	if len(b.data) < 8 {
+		panic("BufferSource ran out of data")
+	}
+
+	v := binary.BigEndian.Uint64(b.data)
+	b.data = b.data[8:]
+	return v
+}
+
+// Int63 returns the top 63 bits of b.Uint64().
+func (b *BufferSource) Int63() int64 {
+	return int64(b.Uint64() >> 1)
+}
+
+// Remaining returns the number of unused bytes left in the buffer.
+func (b *BufferSource) Remaining() int {
+	return len(b.data)
+}
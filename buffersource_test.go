@@ -0,0 +1,58 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBufferSourceRejection checks the example from the comments for BufferSource, where a crafted buffer
+// forces exactly one rejection in Uint32n().
+func TestBufferSourceRejection(t *testing.T) {
+	t.Parallel()
+	src := NewBufferSource([]byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+	})
+	require.Equal(t, uint32(2), Uint32n(src, 3))
+	require.Equal(t, 0, src.Remaining())
+}
+
+// TestBufferSourceValues checks that BufferSource interprets each 8-byte chunk as a big-endian uint64.
+func TestBufferSourceValues(t *testing.T) {
+	t.Parallel()
+	src := NewBufferSource([]byte{
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x12, 0x34,
+	})
+	require.Equal(t, 26, src.Remaining())
+	require.Equal(t, uint64(0x0123456789abcdef), src.Uint64())
+	require.Equal(t, int64(0x7fffffffffffffff), src.Int63())
+	require.Equal(t, uint32(0x80000000), randUint32(src))
+	require.Equal(t, 2, src.Remaining())
+	require.PanicsWithValue(t, "BufferSource ran out of data", func() {
+		src.Int63()
+	})
+}
+
+// TestBufferSourceMatchesTestSource checks that Uint32n() on a BufferSource behaves the same as on a
+// testSource with the first 4 bytes of each chunk as its values.
+func TestBufferSourceMatchesTestSource(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint32{3, 1000, 0x80000001} {
+		for r := 0; r < 3; r++ {
+			testSrc := makeTestSource(r, 0x12345678)
+			expected := Uint32n(&testSrc, n)
+
+			var data []byte
+			for _, v := range makeTestSource(r, 0x12345678).vs {
+				data = append(data, byte(v>>24), byte(v>>16), byte(v>>8), byte(v), 0xaa, 0xbb, 0xcc, 0xdd)
+			}
+			src := NewBufferSource(data)
+			require.Equal(t, expected, Uint32n(src, n), "n=%d r=%d", n, r)
+			require.Equal(t, 8*(len(testSrc.vs)-testSrc.callCount), src.Remaining(), "n=%d r=%d", n, r)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package random
+
+// Bytes fills b with uniformly-distributed pseudo-random bytes.
+//
+// If src is a Source64, each call to src.Uint64() fills 8 bytes. Otherwise, since src.Int63() only returns 63
+// bits, each call fills 7 bytes from the low 56 bits of the result, like rand.Read(). In both cases, the bytes
+// of each value are used starting from the least significant one. If len(b) isn't a multiple of 8 (or 7), the
+// last value only fills the remaining bytes of b, and the rest of its bytes are discarded; use a Reader to
+// keep them for later.
+func Bytes(src Source, b []byte) {
+	if src64, ok := src.(Source64); ok {
+		for len(b) > 0 {
+			v := src64.Uint64()
+			for i := 0; i < 8 && len(b) > 0; i++ {
+				b[0] = byte(v)
+				b = b[1:]
+				v >>= 8
+			}
+		}
+		return
+	}
+
+	for len(b) > 0 {
+		v := src.Int63()
+		for i := 0; i < 7 && len(b) > 0; i++ {
+			b[0] = byte(v)
+			b = b[1:]
+			v >>= 8
+		}
+	}
+}
+
+// A Reader is an io.Reader that returns uniformly-distributed pseudo-random bytes from a Source. The bytes
+// are the same as the ones that Bytes() would return, except that unused bytes of each value are kept for the
+// next call to Read(), so reading in chunks returns the same bytes as a single large read.
+//
+// A Reader is not safe for concurrent use by multiple goroutines.
+type Reader struct {
+	src   Source
+	src64 Source64
+	// val holds the unused bytes of the last value, least significant first, and valLen is the number of them.
+	val    uint64
+	valLen int
+}
+
+// NewReader returns a new Reader that uses src.
+func NewReader(src Source) *Reader {
+	src64, _ := src.(Source64)
+	return &Reader{src: src, src64: src64}
+}
+
+// Read fills p with pseudo-random bytes. It always returns len(p), nil.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	for n = 0; n < len(p); n++ {
+		if r.valLen == 0 {
+			if r.src64 != nil {
+				r.val, r.valLen = r.src64.Uint64(), 8
+			} else {
+				r.val, r.valLen = uint64(r.src.Int63()), 7
+			}
+		}
+		p[n] = byte(r.val)
+		r.val >>= 8
+		r.valLen--
+	}
+	return n, nil
+}
@@ -0,0 +1,126 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBytesSource checks that Bytes() uses the low 56 bits of each call to Int63() for a Source, least
+// significant byte first, including for a partial final value.
+func TestBytesSource(t *testing.T) {
+	t.Parallel()
+	src := int63Source{vs: []int64{0x7f06050403020100, 0x7f0d0c0b0a0908ff, 0x7fffffffff101110}}
+	b := make([]byte, 17)
+	Bytes(&src, b)
+	require.Equal(t, []byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06,
+		0xff, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d,
+		0x10, 0x11, 0x10,
+	}, b)
+	require.Equal(t, 3, src.callCount)
+}
+
+// TestBytesSource64 checks that Bytes() uses all 64 bits of each call to Uint64() for a Source64, least
+// significant byte first, including for a partial final value.
+func TestBytesSource64(t *testing.T) {
+	t.Parallel()
+	src := testUint64Source{vs: []uint64{0x0706050403020100, 0xffeeddccbbaa9988}}
+	b := make([]byte, 11)
+	Bytes(&src, b)
+	require.Equal(t, []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x88, 0x99, 0xaa}, b)
+	require.Equal(t, 2, src.callCount)
+}
+
+// TestBytesDeterministic checks that Bytes() returns the same bytes for the same seed.
+func TestBytesDeterministic(t *testing.T) {
+	t.Parallel()
+	for n := 0; n < 50; n++ {
+		b1 := make([]byte, n)
+		Bytes(rand.NewSource(int64(n)), b1)
+		b2 := make([]byte, n)
+		Bytes(rand.NewSource(int64(n)), b2)
+		require.Equal(t, b1, b2)
+	}
+}
+
+// TestBytesEmpty checks that Bytes() doesn't use any randomness for an empty slice.
+func TestBytesEmpty(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	Bytes(&src, nil)
+	require.Equal(t, 0, src.callCount)
+}
+
+// testReaderChunks checks that reading from a Reader in chunks of the given sizes returns the same bytes as
+// a single call to Bytes() for the same seed.
+func testReaderChunks(t *testing.T, newSrc func() Source, chunkSizes []int) {
+	total := 0
+	for _, size := range chunkSizes {
+		total += size
+	}
+	expected := make([]byte, total)
+	Bytes(newSrc(), expected)
+
+	r := NewReader(newSrc())
+	actual := make([]byte, 0, total)
+	for _, size := range chunkSizes {
+		chunk := make([]byte, size)
+		n, err := r.Read(chunk)
+		require.NoError(t, err)
+		require.Equal(t, size, n)
+		actual = append(actual, chunk...)
+	}
+	require.Equal(t, expected, actual, "chunkSizes=%v", chunkSizes)
+}
+
+// TestReaderChunks checks that reading from a Reader in awkward chunk sizes doesn't waste any bytes, for both
+// a Source and a Source64.
+func TestReaderChunks(t *testing.T) {
+	t.Parallel()
+	chunkSizesList := [][]int{
+		{0},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{3, 5, 7, 0, 11, 13, 2},
+		{6, 1, 8, 9, 15, 16, 17},
+		{100},
+	}
+	for _, chunkSizes := range chunkSizesList {
+		testReaderChunks(t, func() Source {
+			return rand.NewSource(1)
+		}, chunkSizes)
+		testReaderChunks(t, func() Source {
+			// Hide the Uint64() method, so that Int63() is used.
+			return NewStatsSource(rand.NewSource(1))
+		}, chunkSizes)
+	}
+}
+
+// The BenchmarkBytes* functions benchmark Bytes() against filling a slice by calling Uint32n() for each byte.
+
+const bytesN = 4096
+
+var bytesResult []byte
+
+func BenchmarkBytes(b *testing.B) {
+	src := rand.NewSource(13)
+	buf := make([]byte, bytesN)
+	for n := 0; n < b.N; n++ {
+		Bytes(src, buf)
+	}
+	bytesResult = buf
+}
+
+var bytesUint32nResult []byte
+
+func BenchmarkBytesUint32n(b *testing.B) {
+	src := rand.NewSource(13)
+	buf := make([]byte, bytesN)
+	for n := 0; n < b.N; n++ {
+		for i := range buf {
+			buf[i] = byte(Uint32n(src, 256))
+		}
+	}
+	bytesUint32nResult = buf
+}
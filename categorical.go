@@ -0,0 +1,63 @@
+package random
+
+import "math"
+
+// A Categorical picks one of k categories given unnormalized log-probabilities (logits), which is useful when
+// the logits change on every draw, e.g. in an inference loop: unlike DiscreteDistribution or AliasTable, no
+// setup is needed per set of logits, and no softmax normalization is done.
+//
+// This uses the Gumbel-max trick: adding independent standard Gumbel noise -log(-log(U)) to each logit and
+// taking the index of the largest sum picks index i with probability exp(logits[i]) / Σⱼ exp(logits[j]). This
+// needs no scratch space, so SampleLogits() never allocates; a Categorical just checks that each set of logits
+// has the expected length.
+type Categorical struct {
+	k int
+}
+
+// NewCategorical returns a new Categorical for k categories. k must be positive.
+func NewCategorical(k int) *Categorical {
+	if k <= 0 {
+		panic("k must be positive in call to NewCategorical")
+	}
+
+	return &Categorical{k: k}
+}
+
+// K returns the number of categories c was constructed with.
+func (c *Categorical) K() int {
+	return c.k
+}
+
+// SampleLogits returns index i with probability exp(logits[i]) / Σⱼ exp(logits[j]). logits must have length
+// c.K(), and each logit must be finite or -Inf (which means that index is never picked), but not all -Inf.
+//
+// This draws one value from src for each logit that isn't -Inf.
+func (c *Categorical) SampleLogits(src Source, logits []float64) int {
+	if len(logits) != c.k {
+		panic("len(logits) must equal k in call to SampleLogits")
+	}
+
+	best := -1
+	bestScore := math.Inf(-1)
+	for i, logit := range logits {
+		if math.IsNaN(logit) || math.IsInf(logit, 1) {
+			panic("logits must be finite or -Inf in call to SampleLogits")
+		}
+		if math.IsInf(logit, -1) {
+			continue
+		}
+
+		// Float64() is in [0, 1), so the noise is in [-Inf, Inf); -Inf (for U == 0) just means that this
+		// index loses, unless it's the only candidate.
+		score := logit - math.Log(-math.Log(Float64(src)))
+		if best == -1 || score > bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+
+	if best == -1 {
+		panic("logits must not all be -Inf in call to SampleLogits")
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCategoricalProbabilities checks that SampleLogits() picks each index with probability proportional to
+// the exponential of its logit.
+func TestCategoricalProbabilities(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct {
+		logits   []float64
+		expected []float64
+	}{
+		{[]float64{0, math.Ln2}, []float64{1.0 / 3, 2.0 / 3}},
+		{[]float64{0}, []float64{1}},
+		{[]float64{1, 1, 1, 1}, []float64{0.25, 0.25, 0.25, 0.25}},
+		{[]float64{math.Log(3), math.Inf(-1), 0}, []float64{0.75, 0, 0.25}},
+		// Large logits would overflow a naive softmax.
+		{[]float64{1000, 1000 + math.Log(4)}, []float64{0.2, 0.8}},
+	}
+	for _, tc := range testCases {
+		src := rand.NewSource(1)
+		c := NewCategorical(len(tc.logits))
+		counts := make([]int, len(tc.logits))
+		for i := 0; i < trials; i++ {
+			counts[c.SampleLogits(src, tc.logits)]++
+		}
+		for i, count := range counts {
+			requireBinomialCount(t, trials, tc.expected[i], count, "logits=%v i=%d", tc.logits, i)
+		}
+	}
+}
+
+// TestCategoricalNoAllocs checks that SampleLogits() doesn't allocate.
+func TestCategoricalNoAllocs(t *testing.T) {
+	src := rand.NewSource(1)
+	c := NewCategorical(3)
+	logits := []float64{0.5, -1, 2}
+	allocs := testing.AllocsPerRun(100, func() {
+		c.SampleLogits(src, logits)
+	})
+	require.Equal(t, 0.0, allocs)
+}
+
+// TestCategoricalInvalid checks that NewCategorical() and SampleLogits() panic for invalid arguments.
+func TestCategoricalInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "k must be positive in call to NewCategorical", func() {
+		NewCategorical(0)
+	})
+
+	c := NewCategorical(2)
+	require.PanicsWithValue(t, "len(logits) must equal k in call to SampleLogits", func() {
+		c.SampleLogits(&src, []float64{0})
+	})
+	for _, logit := range []float64{math.NaN(), math.Inf(1)} {
+		require.PanicsWithValue(t, "logits must be finite or -Inf in call to SampleLogits", func() {
+			c.SampleLogits(&src, []float64{logit, 0})
+		}, "logit=%f", logit)
+	}
+	require.PanicsWithValue(t, "logits must not all be -Inf in call to SampleLogits", func() {
+		c.SampleLogits(&src, []float64{math.Inf(-1), math.Inf(-1)})
+	})
+}
@@ -0,0 +1,40 @@
+package random
+
+// CombineSources returns a Source whose values are the XOR of the values of a and b, which is useful for
+// defense in depth, e.g. combining a fast PRNG with a Source backed by crypto/rand: the XOR of two values is
+// uniformly distributed if either one is uniformly distributed and independent of the other, so the combined
+// Source is at least as good as the better of a and b, as long as they're independent. If they aren't, e.g.
+// if b is derived from a, the result can be arbitrarily bad; in the extreme, combining two copies of a Source
+// with the same state always returns 0.
+//
+// If a and b are both Source64s, the returned Source is also a Source64, whose Uint64() values are the XOR of
+// those of a and b; otherwise, it only implements Source.
+func CombineSources(a, b Source) Source {
+	a64, aOK := a.(Source64)
+	b64, bOK := b.(Source64)
+	if aOK && bOK {
+		return combinedSource64{combinedSource{a, b}, a64, b64}
+	}
+	return combinedSource{a, b}
+}
+
+// A combinedSource is the Source returned by CombineSources().
+type combinedSource struct {
+	a, b Source
+}
+
+// Int63 returns the XOR of a.Int63() and b.Int63(), masked to 63 bits.
+func (s combinedSource) Int63() int64 {
+	return (s.a.Int63() ^ s.b.Int63()) & (1<<63 - 1)
+}
+
+// A combinedSource64 is the Source64 returned by CombineSources().
+type combinedSource64 struct {
+	combinedSource
+	a64, b64 Source64
+}
+
+// Uint64 returns the XOR of a.Uint64() and b.Uint64().
+func (s combinedSource64) Uint64() uint64 {
+	return s.a64.Uint64() ^ s.b64.Uint64()
+}
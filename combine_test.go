@@ -0,0 +1,44 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCombineSourcesXOR checks that CombineSources() returns the XOR of the streams of two deterministic
+// Sources.
+func TestCombineSourcesXOR(t *testing.T) {
+	t.Parallel()
+	a := &int63Source{vs: []int64{0, 1<<63 - 1, 0x0f0f0f0f0f0f0f0f}}
+	b := &int63Source{vs: []int64{1<<63 - 1, 1<<63 - 1, 0x00ff00ff00ff00ff}}
+	src := CombineSources(a, b)
+	_, ok := src.(Source64)
+	require.False(t, ok)
+	require.Equal(t, int64(1<<63-1), src.Int63())
+	require.Equal(t, int64(0), src.Int63())
+	require.Equal(t, int64(0x0ff00ff00ff00ff0), src.Int63())
+
+	expectedA, expectedB := NewSplitMix64(1), NewSplitMix64(2)
+	src = CombineSources(NewSplitMix64(1), NewSplitMix64(2))
+	_, ok = src.(Source64)
+	require.True(t, ok)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expectedA.Int63()^expectedB.Int63(), src.Int63(), "i=%d", i)
+		require.Equal(t, expectedA.Uint64()^expectedB.Uint64(), src.(Source64).Uint64(), "i=%d", i)
+	}
+}
+
+// TestCombineSourcesUniform checks that combining a uniform Source with a very non-uniform independent one
+// (which always returns the same value) gives a uniform Source.
+func TestCombineSourcesUniform(t *testing.T) {
+	t.Parallel()
+	constant := NewFaultSource(rand.NewSource(1), 0)
+	constant.FailWithValue(0x123456789abcdef)
+	require.Error(t, TestSourceUniformity(constant, 10, 100000))
+
+	src := CombineSources(rand.NewSource(2), constant)
+	require.NoError(t, TestSourceUniformity(src, 10, 100000))
+	require.NoError(t, TestSourceUniformity(src, 1000, 100000))
+}
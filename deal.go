@@ -0,0 +1,40 @@
+package random
+
+// Deal shuffles a deck of the cards 0 to deckSize-1 (inclusive) and deals perHand cards to each of hands
+// players, returning the hands in order. deckSize, hands, and perHand must be non-negative, and hands*perHand
+// must be at most deckSize.
+//
+// Only the hands*perHand cards that are dealt are shuffled, using ShuffleN(), and each hand is a consecutive run
+// of them, which has the same distribution as dealing them one at a time around the table. The hands share a
+// backing array, but each one has its capacity limited to its length, so appending to one doesn't affect the
+// others.
+func Deal(src Source, deckSize, hands, perHand int) [][]int {
+	if deckSize < 0 {
+		panic("deckSize must be non-negative in call to Deal")
+	}
+	if hands < 0 {
+		panic("hands must be non-negative in call to Deal")
+	}
+	if perHand < 0 {
+		panic("perHand must be non-negative in call to Deal")
+	}
+	// Check hands*perHand > deckSize without overflowing.
+	if perHand > 0 && hands > deckSize/perHand {
+		panic("hands*perHand must be at most deckSize in call to Deal")
+	}
+
+	deck := make([]int, deckSize)
+	for i := range deck {
+		deck[i] = i
+	}
+	ShuffleN(src, deckSize, hands*perHand, func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+
+	out := make([][]int, hands)
+	for h := range out {
+		start := h * perHand
+		out[h] = deck[start : start+perHand : start+perHand]
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDealDistinct checks that Deal() returns the right number of hands of the right size, with distinct cards
+// in range.
+func TestDealDistinct(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for i := 0; i < 100; i++ {
+		hands := Deal(src, 52, 4, 13)
+		require.Equal(t, 4, len(hands))
+		seen := make(map[int]bool)
+		for h, hand := range hands {
+			require.Equal(t, 13, len(hand), "h=%d", h)
+			for _, card := range hand {
+				require.True(t, card >= 0 && card < 52, "h=%d card=%d", h, card)
+				require.False(t, seen[card], "h=%d card=%d", h, card)
+				seen[card] = true
+			}
+		}
+	}
+}
+
+// TestDealUniform checks that over many deals, each card lands in each hand (or stays undealt) with the
+// expected probability.
+func TestDealUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const deckSize = 7
+	const numHands = 3
+	const perHand = 2
+	src := rand.NewSource(2)
+	// counts[card][h] is the number of times card landed in hand h.
+	var counts [deckSize][numHands]int
+	for trial := 0; trial < trials; trial++ {
+		for h, hand := range Deal(src, deckSize, numHands, perHand) {
+			for _, card := range hand {
+				counts[card][h]++
+			}
+		}
+	}
+	for card := range counts {
+		for h, count := range counts[card] {
+			requireBinomialCount(t, trials, float64(perHand)/deckSize, count, "card=%d h=%d", card, h)
+		}
+	}
+}
+
+// TestDealHandsIndependent checks that appending to one hand doesn't affect the next one.
+func TestDealHandsIndependent(t *testing.T) {
+	t.Parallel()
+	hands := Deal(rand.NewSource(3), 10, 2, 3)
+	second := append([]int(nil), hands[1]...)
+	_ = append(hands[0], -1)
+	require.Equal(t, second, hands[1])
+}
+
+// TestDealEmpty checks that Deal() handles zero hands or zero cards per hand.
+func TestDealEmpty(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, [][]int{}, Deal(rand.NewSource(4), 5, 0, 3))
+	require.Equal(t, [][]int{{}, {}}, Deal(rand.NewSource(4), 0, 2, 0))
+}
+
+// TestDealInvalid checks that Deal() panics for invalid arguments.
+func TestDealInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "deckSize must be non-negative in call to Deal", func() {
+		Deal(&src, -1, 0, 0)
+	})
+	require.PanicsWithValue(t, "hands must be non-negative in call to Deal", func() {
+		Deal(&src, 52, -1, 0)
+	})
+	require.PanicsWithValue(t, "perHand must be non-negative in call to Deal", func() {
+		Deal(&src, 52, 4, -1)
+	})
+	require.PanicsWithValue(t, "hands*perHand must be at most deckSize in call to Deal", func() {
+		Deal(&src, 52, 4, 14)
+	})
+	require.PanicsWithValue(t, "hands*perHand must be at most deckSize in call to Deal", func() {
+		Deal(&src, 52, 1<<30, 1<<30)
+	})
+}
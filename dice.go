@@ -0,0 +1,40 @@
+package random
+
+import "math"
+
+// RollDice returns the sum of count rolls of a fair die with the given number of sides, numbered 1 to sides
+// (inclusive), e.g. RollDice(src, 3, 6) for "3d6". Each roll is Uint32n(src, sides)+1. count must be
+// non-negative, and sides must be in the range 1 to 2³²-1 (inclusive).
+func RollDice(src Source, count, sides int) int {
+	checkDice(count, sides, "RollDice")
+
+	sum := 0
+	for i := 0; i < count; i++ {
+		sum += int(Uint32n(src, uint32(sides))) + 1
+	}
+	return sum
+}
+
+// RollDiceDetailed is like RollDice(), except that it returns the individual rolls instead of their sum. It
+// makes the same calls to src as RollDice(), so for the same state of src, the rolls add up to what RollDice()
+// would return.
+func RollDiceDetailed(src Source, count, sides int) []int {
+	checkDice(count, sides, "RollDiceDetailed")
+
+	rolls := make([]int, count)
+	for i := range rolls {
+		rolls[i] = int(Uint32n(src, uint32(sides))) + 1
+	}
+	return rolls
+}
+
+// checkDice panics if count is negative, or if sides isn't a valid bound for Uint32n(). funcName is the name
+// of the calling function, for the panic message.
+func checkDice(count, sides int, funcName string) {
+	if count < 0 {
+		panic("count must be non-negative in call to " + funcName)
+	}
+	if sides < 1 || uint64(sides) > math.MaxUint32 {
+		panic("sides must be in [1, 2³²-1] in call to " + funcName)
+	}
+}
@@ -0,0 +1,92 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRollDiceD6 checks that a single d6 is uniform over 1 to 6.
+func TestRollDiceD6(t *testing.T) {
+	t.Parallel()
+	const trials = 60000
+	src := rand.NewSource(1)
+	var counts [7]int
+	for i := 0; i < trials; i++ {
+		roll := RollDice(src, 1, 6)
+		require.True(t, roll >= 1 && roll <= 6, "roll=%d", roll)
+		counts[roll]++
+	}
+	for roll := 1; roll <= 6; roll++ {
+		requireBinomialCount(t, trials, 1.0/6, counts[roll], "roll=%d", roll)
+	}
+}
+
+// TestRollDice2D6 checks that 2d6 has the triangular distribution peaking at 7, i.e. that s comes up with
+// probability (6 - |s-7|)/36.
+func TestRollDice2D6(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	src := rand.NewSource(2)
+	var counts [13]int
+	for i := 0; i < trials; i++ {
+		counts[RollDice(src, 2, 6)]++
+	}
+	require.Equal(t, 0, counts[0]+counts[1])
+	for s := 2; s <= 12; s++ {
+		d := s - 7
+		if d < 0 {
+			d = -d
+		}
+		requireBinomialCount(t, trials, float64(6-d)/36, counts[s], "s=%d", s)
+	}
+}
+
+// TestRollDiceDetailedMatchesRollDice checks that RollDiceDetailed() returns rolls that add up to what
+// RollDice() returns for the same seed.
+func TestRollDiceDetailedMatchesRollDice(t *testing.T) {
+	t.Parallel()
+	src1 := rand.NewSource(3)
+	src2 := rand.NewSource(3)
+	for count := 0; count < 20; count++ {
+		rolls := RollDiceDetailed(src1, count, 20)
+		require.Equal(t, count, len(rolls))
+		sum := 0
+		for _, roll := range rolls {
+			require.True(t, roll >= 1 && roll <= 20, "roll=%d", roll)
+			sum += roll
+		}
+		require.Equal(t, RollDice(src2, count, 20), sum, "count=%d", count)
+	}
+}
+
+// TestRollDiceTrivial checks that RollDice() doesn't use any randomness for count == 0 or 1-sided dice.
+func TestRollDiceTrivial(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, 0, RollDice(&src, 0, 6))
+	require.Empty(t, RollDiceDetailed(&src, 0, 6))
+	require.Equal(t, 0, src.callCount)
+	src = testSource{vs: []uint32{0, 0, 0}}
+	require.Equal(t, 3, RollDice(&src, 3, 1))
+}
+
+// TestRollDiceInvalid checks that RollDice() and RollDiceDetailed() panic for negative count or invalid
+// sides.
+func TestRollDiceInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "count must be non-negative in call to RollDice", func() {
+		RollDice(&src, -1, 6)
+	})
+	require.PanicsWithValue(t, "sides must be in [1, 2³²-1] in call to RollDice", func() {
+		RollDice(&src, 1, 0)
+	})
+	require.PanicsWithValue(t, "count must be non-negative in call to RollDiceDetailed", func() {
+		RollDiceDetailed(&src, -1, 6)
+	})
+	require.PanicsWithValue(t, "sides must be in [1, 2³²-1] in call to RollDiceDetailed", func() {
+		RollDiceDetailed(&src, 1, -6)
+	})
+}
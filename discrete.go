@@ -0,0 +1,104 @@
+package random
+
+import (
+	"math"
+	"sort"
+)
+
+// A DiscreteDistribution picks indices with probability proportional to a list of weights, using a binary
+// search over the cumulative weights. Picking takes O(log n) time, which is slower than AliasTable, but
+// construction is cheaper, so it's better for small weight lists that change often.
+type DiscreteDistribution struct {
+	// cumWeights[i] is the sum of weights[0] to weights[i] (inclusive).
+	cumWeights []float64
+	// last is the index of the last non-zero weight.
+	last int
+}
+
+// NewDiscrete returns a new DiscreteDistribution for the given weights, which must be non-empty, finite,
+// non-negative, and not all zero.
+func NewDiscrete(weights []float64) *DiscreteDistribution {
+	if len(weights) == 0 {
+		panic("weights must be non-empty in call to NewDiscrete")
+	}
+
+	d := &DiscreteDistribution{cumWeights: make([]float64, len(weights))}
+	var sum float64
+	for i, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("weights must be finite and non-negative in call to NewDiscrete")
+		}
+		if w > 0 {
+			d.last = i
+		}
+		sum += w
+		d.cumWeights[i] = sum
+	}
+
+	if sum == 0 {
+		panic("weights must not all be zero in call to NewDiscrete")
+	}
+
+	return d
+}
+
+// Len returns the number of weights d was constructed with.
+func (d *DiscreteDistribution) Len() int {
+	return len(d.cumWeights)
+}
+
+// search returns the smallest index i such that d.cumWeights[i] > u, which is the index whose weight
+// covers u, for u in the range 0 to the total weight (exclusive).
+func (d *DiscreteDistribution) search(u float64) int {
+	i := sort.SearchFloat64s(d.cumWeights, u)
+	// sort.SearchFloat64s returns the smallest index with d.cumWeights[i] >= u, so skip past entries equal
+	// to u, which includes any zero weights.
+	for i < len(d.cumWeights) && d.cumWeights[i] <= u {
+		i++
+	}
+	// u is less than the total weight, but the multiplication in Next() can round up to it.
+	if i > d.last {
+		i = d.last
+	}
+	return i
+}
+
+// Next returns an index in the range 0 to d.Len()-1 (inclusive), with probability proportional to its weight.
+// This uses one call to Float64().
+func (d *DiscreteDistribution) Next(src Source) int {
+	return d.search(Float64(src) * d.cumWeights[len(d.cumWeights)-1])
+}
+
+// WeightedIndex returns an index into weights with probability proportional to its weight, for a one-off draw
+// where building a DiscreteDistribution or an AliasTable wouldn't pay off. The weights must be non-negative,
+// not all zero, and their sum must fit in a uint64.
+//
+// Unlike DiscreteDistribution, which uses Float64(), this uses integer arithmetic, so the probabilities are
+// exact: it draws r := Uint64n(src, total), where total is the sum of the weights, and then returns the first
+// index i such that r is less than the sum of weights[0] to weights[i] (inclusive). This takes two passes over
+// weights, and never returns an index with zero weight.
+func WeightedIndex(src Source, weights []int) int {
+	var total uint64
+	for _, w := range weights {
+		if w < 0 {
+			panic("weights must be non-negative in call to WeightedIndex")
+		}
+		sum := total + uint64(w)
+		if sum < total {
+			panic("sum of weights must fit in a uint64 in call to WeightedIndex")
+		}
+		total = sum
+	}
+	if total == 0 {
+		panic("weights must not all be zero in call to WeightedIndex")
+	}
+
+	r := Uint64n(src, total)
+	for i, w := range weights {
+		if r < uint64(w) {
+			return i
+		}
+		r -= uint64(w)
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,145 @@
+package random
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscreteFrequencies checks that the empirical frequencies of DiscreteDistribution.Next() converge to the
+// normalized weights.
+func TestDiscreteFrequencies(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	weightsList := [][]float64{{1, 1, 2}, {1}, {0, 1, 0, 3}, {0.5, 0, 0}}
+	for i, weights := range weightsList {
+		d := NewDiscrete(weights)
+		require.Equal(t, len(weights), d.Len())
+		src := rand.NewSource(int64(i))
+		counts := make([]int, len(weights))
+		for j := 0; j < trials; j++ {
+			counts[d.Next(src)]++
+		}
+		var sum float64
+		for _, w := range weights {
+			sum += w
+		}
+		for j, count := range counts {
+			requireBinomialCount(t, trials, weights[j]/sum, count, "weights=%v j=%d", weights, j)
+		}
+	}
+}
+
+// TestDiscreteSearchBoundaries checks that the binary search picks the right bucket at the boundaries of the
+// cumulative weights.
+func TestDiscreteSearchBoundaries(t *testing.T) {
+	t.Parallel()
+	d := NewDiscrete([]float64{1, 1, 2})
+	tests := []struct {
+		u        float64
+		expected int
+	}{
+		{0, 0},
+		{math.Nextafter(1, 0), 0},
+		{1, 1},
+		{math.Nextafter(2, 0), 1},
+		{2, 2},
+		{math.Nextafter(4, 0), 2},
+		// This can't happen with exact arithmetic, but can with rounding.
+		{4, 2},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, d.search(test.u), "u=%v", test.u)
+	}
+
+	// Zero weights should never be picked, even at their boundaries.
+	d = NewDiscrete([]float64{0, 1, 0, 0, 1, 0})
+	require.Equal(t, 1, d.search(0))
+	require.Equal(t, 4, d.search(1))
+	require.Equal(t, 4, d.search(2))
+}
+
+// TestDiscreteInvalid checks that NewDiscrete() panics for invalid weights.
+func TestDiscreteInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		weights []float64
+		message string
+	}{
+		{nil, "weights must be non-empty in call to NewDiscrete"},
+		{[]float64{1, -1}, "weights must be finite and non-negative in call to NewDiscrete"},
+		{[]float64{math.NaN()}, "weights must be finite and non-negative in call to NewDiscrete"},
+		{[]float64{0, 0, 0}, "weights must not all be zero in call to NewDiscrete"},
+	}
+	for _, test := range tests {
+		require.PanicsWithValue(t, test.message, func() {
+			NewDiscrete(test.weights)
+		}, "weights=%v", test.weights)
+	}
+}
+
+// TestWeightedIndexFrequencies checks that WeightedIndex() picks each index with probability proportional to
+// its weight, and never picks an index with zero weight.
+func TestWeightedIndexFrequencies(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := [][]int{
+		{1, 0, 3},
+		{5},
+		{0, 0, 2, 0},
+		{1, 2, 3, 4},
+	}
+	for _, weights := range testCases {
+		src := rand.NewSource(1)
+		total := 0
+		for _, w := range weights {
+			total += w
+		}
+		counts := make([]int, len(weights))
+		for i := 0; i < trials; i++ {
+			counts[WeightedIndex(src, weights)]++
+		}
+		for i, count := range counts {
+			if weights[i] == 0 {
+				require.Equal(t, 0, count, "weights=%v i=%d", weights, i)
+				continue
+			}
+			requireBinomialCount(t, trials, float64(weights[i])/float64(total), count, "weights=%v i=%d", weights, i)
+		}
+	}
+}
+
+// TestWeightedIndexBoundaries checks that WeightedIndex() maps the draw from Uint64n() to the right index at
+// the boundaries between weights.
+func TestWeightedIndexBoundaries(t *testing.T) {
+	t.Parallel()
+	weights := []int{1, 0, 3}
+	// Uint64n(src, 4) returns the top 2 bits of a Source64's value.
+	for r, expected := range []int{0, 2, 2, 2} {
+		src := &testUint64Source{vs: []uint64{uint64(r) << 62}}
+		require.Equal(t, expected, WeightedIndex(src, weights), "r=%d", r)
+	}
+}
+
+// TestWeightedIndexInvalid checks that WeightedIndex() panics for negative weights, all-zero weights, or
+// weights whose sum overflows.
+func TestWeightedIndexInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "weights must be non-negative in call to WeightedIndex", func() {
+		WeightedIndex(&src, []int{1, -1})
+	})
+	for _, weights := range [][]int{nil, {0}, {0, 0}} {
+		require.PanicsWithValue(t, "weights must not all be zero in call to WeightedIndex", func() {
+			WeightedIndex(&src, weights)
+		}, "weights=%v", weights)
+	}
+	if bits.UintSize == 64 {
+		require.PanicsWithValue(t, "sum of weights must fit in a uint64 in call to WeightedIndex", func() {
+			WeightedIndex(&src, []int{math.MaxInt, math.MaxInt, 2})
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package random
+
+import (
+	"math"
+	"math/bits"
+)
+
+// RandomEdges returns m distinct uniformly-distributed unordered pairs {i, j} of the integers 0 to n-1
+// (inclusive), i.e. the edges of a uniformly-distributed graph with n vertices and m edges (the Erdős–Rényi
+// G(n, m) model). Each pair is returned as [2]int{i, j} with i < j, and the pairs are in no particular order.
+// n must be non-negative, n*(n-1)/2 must fit in a uint64, and m must be in the range 0 to n*(n-1)/2
+// (inclusive).
+//
+// This numbers the n*(n-1)/2 possible edges in triangular order, so that edge e is {i, j} with
+// e = j*(j-1)/2 + i, and then picks m distinct edge numbers with Floyd's algorithm (see Combinations), which
+// uses exactly m draws from Uint64n() and a set of size m, regardless of n.
+func RandomEdges(src Source, n, m int) [][2]int {
+	if n < 0 {
+		panic("n must be non-negative in call to RandomEdges")
+	}
+
+	var numEdges uint64
+	if n >= 2 {
+		hi, lo := bits.Mul64(uint64(n), uint64(n-1))
+		if hi > 1 {
+			panic("n*(n-1)/2 must fit in a uint64 in call to RandomEdges")
+		}
+		numEdges = hi<<63 | lo>>1
+	}
+
+	if m < 0 || uint64(m) > numEdges {
+		panic("m must be in [0, n*(n-1)/2] in call to RandomEdges")
+	}
+
+	chosen := make(map[uint64]bool, m)
+	out := make([][2]int, m)
+	for k, e := 0, numEdges-uint64(m); e < numEdges; k, e = k+1, e+1 {
+		t := Uint64n(src, e+1)
+		if chosen[t] {
+			t = e
+		}
+		chosen[t] = true
+		i, j := edgeFromIndex(t)
+		out[k] = [2]int{int(i), int(j)}
+	}
+	return out
+}
+
+// edgeFromIndex returns the pair {i, j} with i < j such that e = j*(j-1)/2 + i, i.e. the eth edge in the
+// triangular order used by RandomEdges().
+func edgeFromIndex(e uint64) (uint64, uint64) {
+	// j is the largest integer with j*(j-1)/2 <= e, which is floor((1 + sqrt(1 + 8e))/2). Compute an estimate
+	// with floating point, which may be slightly off for large e, and then fix it up.
+	j := uint64((1 + math.Sqrt(1+8*float64(e))) / 2)
+	for triangular(j) > e {
+		j--
+	}
+	// triangular(j+1) == triangular(j) + j, but computing it directly might overflow.
+	for e-triangular(j) >= j {
+		j++
+	}
+	return e - triangular(j), j
+}
+
+// triangular returns j*(j-1)/2, without overflowing if the result fits in a uint64.
+func triangular(j uint64) uint64 {
+	if j%2 == 0 {
+		return (j / 2) * (j - 1)
+	}
+	return j * ((j - 1) / 2)
+}
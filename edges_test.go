@@ -0,0 +1,93 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomEdgesDistinct checks that RandomEdges() returns m distinct valid pairs.
+func TestRandomEdgesDistinct(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, n := range []int{0, 1, 2, 5, 100} {
+		numEdges := n * (n - 1) / 2
+		for _, m := range []int{0, numEdges / 2, numEdges} {
+			edges := RandomEdges(src, n, m)
+			require.Equal(t, m, len(edges), "n=%d m=%d", n, m)
+			seen := make(map[[2]int]bool)
+			for _, edge := range edges {
+				require.True(t, edge[0] >= 0 && edge[0] < edge[1] && edge[1] < n, "n=%d m=%d edge=%v", n, m, edge)
+				require.False(t, seen[edge], "n=%d m=%d edge=%v", n, m, edge)
+				seen[edge] = true
+			}
+		}
+	}
+}
+
+// TestRandomEdgesUniform checks that each edge is picked with probability m/(n*(n-1)/2).
+func TestRandomEdgesUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const n = 5
+	const m = 3
+	src := rand.NewSource(2)
+	counts := make(map[[2]int]int)
+	for trial := 0; trial < trials; trial++ {
+		for _, edge := range RandomEdges(src, n, m) {
+			counts[edge]++
+		}
+	}
+	require.Equal(t, n*(n-1)/2, len(counts))
+	for edge, count := range counts {
+		requireBinomialCount(t, trials, m/float64(n*(n-1)/2), count, "edge=%v", edge)
+	}
+}
+
+// TestEdgeFromIndex checks that edgeFromIndex() inverts the triangular numbering of edges, including for edge
+// numbers large enough that the floating-point estimate is inexact.
+func TestEdgeFromIndex(t *testing.T) {
+	t.Parallel()
+	e := uint64(0)
+	for j := uint64(1); j < 100; j++ {
+		for i := uint64(0); i < j; i++ {
+			ei, ej := edgeFromIndex(e)
+			require.Equal(t, [2]uint64{i, j}, [2]uint64{ei, ej}, "e=%d", e)
+			e++
+		}
+	}
+
+	src := rand.NewSource(3)
+	// maxJ is the largest j such that j*(j-1)/2 fits in a uint64, so not all edge numbers with that j fit.
+	const maxJ = 6074001000
+	for _, j := range []uint64{1 << 26, 1 << 32, maxJ - 1, maxJ} {
+		base := triangular(j)
+		for _, i := range []uint64{0, 1, j / 2, j - 1, Uint64n(src, j)} {
+			if i > math.MaxUint64-base {
+				continue
+			}
+			ei, ej := edgeFromIndex(base + i)
+			require.Equal(t, [2]uint64{i, j}, [2]uint64{ei, ej}, "j=%d i=%d", j, i)
+		}
+	}
+}
+
+// TestRandomEdgesInvalid checks that RandomEdges() panics for invalid arguments.
+func TestRandomEdgesInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-negative in call to RandomEdges", func() {
+		RandomEdges(&src, -1, 0)
+	})
+	require.PanicsWithValue(t, "m must be in [0, n*(n-1)/2] in call to RandomEdges", func() {
+		RandomEdges(&src, 5, 11)
+	})
+	require.PanicsWithValue(t, "m must be in [0, n*(n-1)/2] in call to RandomEdges", func() {
+		RandomEdges(&src, 1, 1)
+	})
+	require.PanicsWithValue(t, "m must be in [0, n*(n-1)/2] in call to RandomEdges", func() {
+		RandomEdges(&src, 5, -1)
+	})
+}
@@ -0,0 +1,87 @@
+package random
+
+import "math"
+
+// An Empirical samples from an empirical distribution given by a list of values and their cumulative weights,
+// e.g. from histogram data, using inverse transform sampling with a binary search like DiscreteDistribution.
+type Empirical struct {
+	values []float64
+	d      DiscreteDistribution
+	// If interpolate is true, the cumulative distribution function is linear between consecutive values;
+	// otherwise, it's a step function.
+	interpolate bool
+}
+
+// NewEmpirical returns a new Empirical whose Next() returns values[i] with probability proportional to
+// cumWeights[i] - cumWeights[i-1] (with cumWeights[-1] taken to be 0). len(cumWeights) must equal
+// len(values), which must be non-zero, and cumWeights must be finite, non-negative, non-decreasing, and not
+// all zero. The weights needn't be normalized.
+func NewEmpirical(values, cumWeights []float64) *Empirical {
+	return newEmpirical(values, cumWeights, false, "NewEmpirical")
+}
+
+// NewInterpolatedEmpirical is like NewEmpirical(), except that it treats (values[i], cumWeights[i]) as points
+// on the (unnormalized) cumulative distribution function, and interpolates linearly between them. That is,
+// Next() returns values[0] with probability proportional to cumWeights[0], and a value uniformly distributed
+// between values[i-1] and values[i] with probability proportional to cumWeights[i] - cumWeights[i-1]. In
+// addition to the requirements of NewEmpirical(), values must be non-decreasing.
+func NewInterpolatedEmpirical(values, cumWeights []float64) *Empirical {
+	return newEmpirical(values, cumWeights, true, "NewInterpolatedEmpirical")
+}
+
+// newEmpirical validates values and cumWeights and returns an Empirical with copies of them. cumWeights[i] is
+// the (unnormalized) cumulative weight of everything up to and including values[i], which is used directly as
+// the cumulative weights of the DiscreteDistribution that picks i. If interpolate is true, values must also be
+// non-decreasing, and Next() interpolates between values[i-1] and values[i] instead of returning values[i].
+// funcName is the name of the calling constructor, for the panic messages.
+func newEmpirical(values, cumWeights []float64, interpolate bool, funcName string) *Empirical {
+	if len(values) == 0 {
+		panic("values must be non-empty in call to " + funcName)
+	}
+	if len(cumWeights) != len(values) {
+		panic("len(cumWeights) must equal len(values) in call to " + funcName)
+	}
+
+	e := &Empirical{
+		values:      append([]float64(nil), values...),
+		d:           DiscreteDistribution{cumWeights: append([]float64(nil), cumWeights...)},
+		interpolate: interpolate,
+	}
+	prev := 0.0
+	for i, w := range cumWeights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("cumWeights must be finite and non-negative in call to " + funcName)
+		}
+		if w < prev {
+			panic("cumWeights must be non-decreasing in call to " + funcName)
+		}
+		if w > prev {
+			e.d.last = i
+		}
+		prev = w
+		if interpolate && i > 0 && values[i] < values[i-1] {
+			panic("values must be non-decreasing in call to " + funcName)
+		}
+	}
+
+	if prev == 0 {
+		panic("cumWeights must not all be zero in call to " + funcName)
+	}
+
+	return e
+}
+
+// Next returns a value from e's empirical distribution. This uses one call to Float64().
+func (e *Empirical) Next(src Source) float64 {
+	cumWeights := e.d.cumWeights
+	u := Float64(src) * cumWeights[len(cumWeights)-1]
+	i := e.d.search(u)
+	if !e.interpolate || i == 0 {
+		return e.values[i]
+	}
+
+	// search() guarantees that cumWeights[i-1] <= u < cumWeights[i], except when u rounds up to the total
+	// weight, in which case clamp the fraction to 1.
+	t := math.Min((u-cumWeights[i-1])/(cumWeights[i]-cumWeights[i-1]), 1)
+	return e.values[i-1] + (e.values[i]-e.values[i-1])*t
+}
@@ -0,0 +1,113 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmpiricalTwoPoint checks that an Empirical with two values returns each one in proportion to its
+// weight.
+func TestEmpiricalTwoPoint(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	e := NewEmpirical([]float64{-2.5, 7}, []float64{3, 10})
+	src := rand.NewSource(1)
+	count := 0
+	for i := 0; i < trials; i++ {
+		switch v := e.Next(src); v {
+		case -2.5:
+			count++
+		case 7:
+		default:
+			require.Fail(t, "unexpected value", "v=%v", v)
+		}
+	}
+	requireBinomialCount(t, trials, 0.3, count)
+}
+
+// TestEmpiricalZeroWeights checks that an Empirical never returns values with zero weight.
+func TestEmpiricalZeroWeights(t *testing.T) {
+	t.Parallel()
+	e := NewEmpirical([]float64{1, 2, 3, 4}, []float64{0, 5, 5, 10})
+	src := rand.NewSource(2)
+	for i := 0; i < 10000; i++ {
+		v := e.Next(src)
+		require.True(t, v == 2 || v == 4, "v=%v", v)
+	}
+	// The largest value of Float64() should still pick the last value with non-zero weight.
+	e = NewEmpirical([]float64{1, 2, 3}, []float64{1, 2, 2})
+	maxSrc := int63Source{vs: []int64{math.MaxInt64}}
+	require.Equal(t, 2.0, e.Next(&maxSrc))
+}
+
+// TestInterpolatedEmpirical checks that an interpolated Empirical has a point mass at the first value and is
+// uniform between consecutive values.
+func TestInterpolatedEmpirical(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	// A point mass of 0.2 at 0, then 0.2 uniform on [0, 1], then 0.6 uniform on [1, 4].
+	e := NewInterpolatedEmpirical([]float64{0, 1, 4}, []float64{1, 2, 5})
+	src := rand.NewSource(3)
+	var zeroCount int
+	var counts [4]int
+	for i := 0; i < trials; i++ {
+		v := e.Next(src)
+		require.True(t, v >= 0 && v <= 4, "v=%v", v)
+		if v == 0 {
+			zeroCount++
+			continue
+		}
+		counts[int(math.Min(v, 3.999))]++
+	}
+	requireBinomialCount(t, trials, 0.2, zeroCount)
+	requireBinomialCount(t, trials, 0.2, counts[0])
+	for i := 1; i < 4; i++ {
+		requireBinomialCount(t, trials, 0.2, counts[i], "i=%d", i)
+	}
+}
+
+// TestEmpiricalCopies checks that NewEmpirical() makes its own copies of its arguments.
+func TestEmpiricalCopies(t *testing.T) {
+	t.Parallel()
+	values := []float64{5}
+	cumWeights := []float64{1}
+	e := NewEmpirical(values, cumWeights)
+	values[0] = 6
+	cumWeights[0] = 0
+	require.Equal(t, 5.0, e.Next(rand.NewSource(1)))
+}
+
+// TestEmpiricalInvalid checks that NewEmpirical() and NewInterpolatedEmpirical() panic for invalid
+// arguments.
+func TestEmpiricalInvalid(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		values, cumWeights []float64
+		message            string
+	}{
+		{nil, nil, "values must be non-empty"},
+		{[]float64{1, 2}, []float64{1}, "len(cumWeights) must equal len(values)"},
+		{[]float64{1, 2}, []float64{-1, 1}, "cumWeights must be finite and non-negative"},
+		{[]float64{1, 2}, []float64{1, math.Inf(1)}, "cumWeights must be finite and non-negative"},
+		{[]float64{1, 2}, []float64{1, math.NaN()}, "cumWeights must be finite and non-negative"},
+		{[]float64{1, 2}, []float64{2, 1}, "cumWeights must be non-decreasing"},
+		{[]float64{1, 2}, []float64{0, 0}, "cumWeights must not all be zero"},
+	}
+	for _, tc := range testCases {
+		require.PanicsWithValue(t, tc.message+" in call to NewEmpirical", func() {
+			NewEmpirical(tc.values, tc.cumWeights)
+		}, "tc=%v", tc)
+		require.PanicsWithValue(t, tc.message+" in call to NewInterpolatedEmpirical", func() {
+			NewInterpolatedEmpirical(tc.values, tc.cumWeights)
+		}, "tc=%v", tc)
+	}
+
+	// Unsorted values are only a problem for interpolation.
+	NewEmpirical([]float64{2, 1}, []float64{1, 2})
+	require.PanicsWithValue(t, "values must be non-decreasing in call to NewInterpolatedEmpirical", func() {
+		NewInterpolatedEmpirical([]float64{2, 1}, []float64{1, 2})
+	})
+}
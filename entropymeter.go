@@ -0,0 +1,56 @@
+package random
+
+// An EntropyMeter wraps a Source and counts the number of random bits drawn from it, at 63 bits per call to
+// Int63(). This is useful for auditing how much randomness an operation uses, e.g. in a lottery: for example,
+// Uint32n(meter, 6) usually reports 63 bits, since it usually needs a single call to Int63(), but it reports a
+// multiple of 63 bits if it has to reject values.
+//
+// Note that the count is of bits drawn, not bits of entropy in the result: Uint32n() only uses the top 32 bits
+// of each call to Int63(), and the result of Uint32n(meter, 6) only has log₂(6) ≈ 2.58 bits of entropy.
+//
+// Like a StatsSource, an EntropyMeter only implements Source, even if the wrapped Source also implements
+// Source64; use an EntropyMeter64 to wrap a Source64.
+type EntropyMeter struct {
+	src  Source
+	bits int64
+}
+
+// NewEntropyMeter returns a new EntropyMeter wrapping src.
+func NewEntropyMeter(src Source) *EntropyMeter {
+	return &EntropyMeter{src: src}
+}
+
+// Int63 returns the result of Int63() on the wrapped Source, and adds 63 to the bit count.
+func (m *EntropyMeter) Int63() int64 {
+	m.bits += 63
+	return m.src.Int63()
+}
+
+// BitsConsumed returns the number of bits drawn since m was created or last reset.
+func (m *EntropyMeter) BitsConsumed() int64 {
+	return m.bits
+}
+
+// Reset sets the bit count back to zero.
+func (m *EntropyMeter) Reset() {
+	m.bits = 0
+}
+
+// An EntropyMeter64 is an EntropyMeter that wraps a Source64 and implements Source64 itself, so that the
+// functions in this package draw the same values from it as from the wrapped Source64. In addition to 63 bits
+// per call to Int63(), it counts 64 bits per call to Uint64().
+type EntropyMeter64 struct {
+	EntropyMeter
+	src64 Source64
+}
+
+// NewEntropyMeter64 returns a new EntropyMeter64 wrapping src.
+func NewEntropyMeter64(src Source64) *EntropyMeter64 {
+	return &EntropyMeter64{EntropyMeter: EntropyMeter{src: src}, src64: src}
+}
+
+// Uint64 returns the result of Uint64() on the wrapped Source64, and adds 64 to the bit count.
+func (m *EntropyMeter64) Uint64() uint64 {
+	m.bits += 64
+	return m.src64.Uint64()
+}
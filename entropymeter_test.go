@@ -0,0 +1,55 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEntropyMeter checks that an EntropyMeter counts 63 bits per call to Int63(), passes through the wrapped
+// Source's values, and that Reset() zeroes the count.
+func TestEntropyMeter(t *testing.T) {
+	t.Parallel()
+	expectedSrc := rand.NewSource(1)
+	meter := NewEntropyMeter(rand.NewSource(1))
+	require.Equal(t, int64(0), meter.BitsConsumed())
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expectedSrc.Int63(), meter.Int63(), "i=%d", i)
+	}
+	require.Equal(t, int64(630), meter.BitsConsumed())
+
+	meter.Reset()
+	require.Equal(t, int64(0), meter.BitsConsumed())
+	meter.Int63()
+	require.Equal(t, int64(63), meter.BitsConsumed())
+}
+
+// TestEntropyMeterRejection checks that an EntropyMeter counts the bits of rejected draws in Uint32n().
+func TestEntropyMeterRejection(t *testing.T) {
+	t.Parallel()
+	src := makeTestSource(2, 0x12345678)
+	meter := NewEntropyMeter(&src)
+	Uint32n(meter, 3)
+	require.Equal(t, int64(3*63), meter.BitsConsumed())
+}
+
+// TestEntropyMeter64 checks that an EntropyMeter64 counts 64 bits per call to Uint64() and 63 bits per call to
+// Int63(), and that the functions in this package see it as a Source64.
+func TestEntropyMeter64(t *testing.T) {
+	t.Parallel()
+	src := &testUint64Source{vs: []uint64{0xffffffffffffffff, 0x123456789abcdef0}}
+	meter := NewEntropyMeter64(src)
+	require.Equal(t, uint64(0xffffffffffffffff), randUint64(meter))
+	require.Equal(t, uint32(0x12345678), randUint32(meter))
+	require.Equal(t, int64(128), meter.BitsConsumed())
+
+	meter.Reset()
+	require.Equal(t, int64(0), meter.BitsConsumed())
+
+	expectedSrc := NewSplitMix64(1)
+	meter = NewEntropyMeter64(NewSplitMix64(1))
+	require.Equal(t, expectedSrc.Int63(), meter.Int63())
+	require.Equal(t, expectedSrc.Uint64(), meter.Uint64())
+	require.Equal(t, int64(127), meter.BitsConsumed())
+}
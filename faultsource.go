@@ -0,0 +1,58 @@
+package random
+
+import "errors"
+
+// ErrInjectedFault is the value that a FaultSource panics with once it fails, unless it's been configured to
+// return a fixed value instead.
+var ErrInjectedFault = errors.New("injected fault")
+
+// A FaultSource wraps a Source and passes through its values for a fixed number of calls to Int63(), and then
+// fails on every call after that, either by panicking with ErrInjectedFault (the default) or by returning a
+// fixed value (see FailWithValue()). This is useful for testing how code that consumes randomness behaves when
+// its Source fails, e.g. a Source backed by crypto/rand that runs into an I/O error, or one that gets stuck.
+//
+// Like a StatsSource, a FaultSource only implements Source, even if the wrapped Source also implements
+// Source64, so that every draw goes through Int63().
+type FaultSource struct {
+	src       Source
+	failAfter int
+	calls     int
+	// If hasFixed is true, calls after the first failAfter return fixed instead of panicking.
+	hasFixed bool
+	fixed    int64
+}
+
+// NewFaultSource returns a new FaultSource wrapping src, which passes through the first failAfter values from
+// src and then panics with ErrInjectedFault. failAfter must be non-negative.
+func NewFaultSource(src Source, failAfter int) *FaultSource {
+	if failAfter < 0 {
+		panic("failAfter must be non-negative in call to NewFaultSource")
+	}
+
+	return &FaultSource{src: src, failAfter: failAfter}
+}
+
+// FailWithValue makes f return v instead of panicking once it fails. v must be in the range 0 to 2⁶³-1
+// (inclusive), like any value returned by Int63().
+func (f *FaultSource) FailWithValue(v int64) {
+	if v < 0 {
+		panic("v must be non-negative in call to FailWithValue")
+	}
+
+	f.hasFixed = true
+	f.fixed = v
+}
+
+// Int63 returns the result of Int63() on the wrapped Source for the first failAfter calls, and then fails as
+// configured. The wrapped Source isn't called once f has failed.
+func (f *FaultSource) Int63() int64 {
+	if f.calls < f.failAfter {
+		f.calls++
+		return f.src.Int63()
+	}
+
+	if f.hasFixed {
+		return f.fixed
+	}
+	panic(ErrInjectedFault)
+}
@@ -0,0 +1,57 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFaultSourcePanics checks that a FaultSource passes through the first failAfter values unchanged, and then
+// panics with ErrInjectedFault on every call.
+func TestFaultSourcePanics(t *testing.T) {
+	t.Parallel()
+	for _, failAfter := range []int{0, 1, 10} {
+		expectedSrc := rand.NewSource(1)
+		src := NewFaultSource(rand.NewSource(1), failAfter)
+		for i := 0; i < failAfter; i++ {
+			require.Equal(t, expectedSrc.Int63(), src.Int63(), "failAfter=%d i=%d", failAfter, i)
+		}
+		for i := 0; i < 3; i++ {
+			require.PanicsWithValue(t, ErrInjectedFault, func() {
+				src.Int63()
+			}, "failAfter=%d i=%d", failAfter, i)
+		}
+	}
+}
+
+// TestFaultSourceFixedValue checks that a FaultSource configured with FailWithValue() returns the fixed value
+// once it fails, without calling the wrapped Source.
+func TestFaultSourceFixedValue(t *testing.T) {
+	t.Parallel()
+	wrapped := makeTestSource(0, 0x12345678)
+	src := NewFaultSource(&wrapped, 1)
+	src.FailWithValue(0)
+	require.Equal(t, int64(0x12345678)<<31, src.Int63())
+	for i := 0; i < 3; i++ {
+		require.Equal(t, int64(0), src.Int63(), "i=%d", i)
+	}
+	require.Equal(t, 1, wrapped.callCount)
+
+	// A fixed value of 0 makes Uint32n() reject forever, so use one that's always accepted.
+	src = NewFaultSource(rand.NewSource(1), 0)
+	src.FailWithValue(1<<63 - 1)
+	require.Equal(t, uint32(2), Uint32n(src, 3))
+}
+
+// TestFaultSourceInvalid checks that NewFaultSource() and FailWithValue() panic for negative arguments.
+func TestFaultSourceInvalid(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "failAfter must be non-negative in call to NewFaultSource", func() {
+		NewFaultSource(rand.NewSource(1), -1)
+	})
+	src := NewFaultSource(rand.NewSource(1), 0)
+	require.PanicsWithValue(t, "v must be non-negative in call to FailWithValue", func() {
+		src.FailWithValue(-1)
+	})
+}
@@ -0,0 +1,33 @@
+package random
+
+import "math"
+
+// Float64 returns a uniformly-distributed pseudo-random float64 value in the range 0.0 to 1.0 (exclusive).
+//
+// Unlike the naive approach of dividing src.Int63() by 2⁶³, which can round up to exactly 1.0 (see
+// https://github.com/golang/go/issues/4965 ), this takes the top 53 bits of src.Int63(), which fit exactly in
+// a float64's significand, and divides by 2⁵³. Since the division by a power of two is also exact, the result
+// is at most (2⁵³-1)/2⁵³ = 1 - 2⁻⁵³ < 1.0.
+func Float64(src Source) float64 {
+	return float64(src.Int63()>>10) / (1 << 53)
+}
+
+// Float32 returns a uniformly-distributed pseudo-random float32 value in the range 0.0 to 1.0 (exclusive).
+//
+// This is like Float64(), except that it takes the top 24 bits of src.Int63(), which fit exactly in a float32's
+// significand, and divides by 2²⁴. Since no rounding happens, the result is at most 1 - 2⁻²⁴ < 1.0.
+func Float32(src Source) float32 {
+	return float32(src.Int63()>>39) / (1 << 24)
+}
+
+// openFloat64 returns a uniformly-distributed pseudo-random float64 value in the range 0.0 (exclusive) to 1.0
+// (inclusive), which is suitable for taking the logarithm of.
+func openFloat64(src Source) float64 {
+	return 1 - Float64(src)
+}
+
+// expFloat64 returns an exponentially-distributed pseudo-random float64 value with rate 1, using inverse
+// transform sampling.
+func expFloat64(src Source) float64 {
+	return -math.Log(openFloat64(src))
+}
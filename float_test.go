@@ -0,0 +1,89 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFloat64Boundaries checks that Float64() returns 0 for the smallest input, 1 - 2⁻⁵³ for the largest
+// input, and values strictly less than 1.0 for all inputs close to the largest one.
+func TestFloat64Boundaries(t *testing.T) {
+	t.Parallel()
+	src := int63Source{vs: []int64{0, 1<<10 - 1}}
+	require.Equal(t, 0.0, Float64(&src))
+	require.Equal(t, 0.0, Float64(&src))
+
+	src = int63Source{vs: []int64{math.MaxInt64}}
+	require.Equal(t, 1-1.0/(1<<53), Float64(&src))
+	require.Equal(t, math.Nextafter(1, 0), 1-1.0/(1<<53))
+
+	prev := 1.0
+	for i := int64(0); i < 1<<20; i++ {
+		src := int63Source{vs: []int64{math.MaxInt64 - i}}
+		f := Float64(&src)
+		require.True(t, f < 1.0, "i=%d f=%v", i, f)
+		require.True(t, f <= prev, "i=%d f=%v prev=%v", i, f, prev)
+		prev = f
+	}
+}
+
+// TestFloat64Uniform checks that Float64() lands in each of a number of equal-sized buckets roughly
+// uniformly.
+func TestFloat64Uniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const bucketCount = 10
+	src := rand.NewSource(1)
+	var buckets [bucketCount]int
+	for i := 0; i < trials; i++ {
+		f := Float64(src)
+		require.True(t, f >= 0 && f < 1, "f=%v", f)
+		buckets[int(f*bucketCount)]++
+	}
+	for i, count := range buckets {
+		requireBinomialCount(t, trials, 1.0/bucketCount, count, "i=%d", i)
+	}
+}
+
+// TestFloat32Boundaries checks that Float32() returns 0 for the smallest input, the float32 just below 1.0
+// for the all-ones input, and values strictly less than 1.0 for all inputs close to the largest one.
+func TestFloat32Boundaries(t *testing.T) {
+	t.Parallel()
+	src := makeTestSource(0, 0)
+	require.Equal(t, float32(0), Float32(&src))
+
+	src = makeTestSource(0, 0xffffffff)
+	require.Equal(t, math.Nextafter32(1, 0), Float32(&src))
+
+	i63src := int63Source{vs: []int64{math.MaxInt64}}
+	require.Equal(t, math.Nextafter32(1, 0), Float32(&i63src))
+
+	prev := float32(1)
+	for v := uint32(0xffffffff); v > 0xffffffff-1<<20; v-- {
+		src := makeTestSource(0, v)
+		f := Float32(&src)
+		require.True(t, f < 1.0, "v=%d f=%v", v, f)
+		require.True(t, f <= prev, "v=%d f=%v prev=%v", v, f, prev)
+		prev = f
+	}
+}
+
+// TestFloat32Uniform is like TestFloat64Uniform, but for Float32().
+func TestFloat32Uniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const bucketCount = 10
+	src := rand.NewSource(1)
+	var buckets [bucketCount]int
+	for i := 0; i < trials; i++ {
+		f := Float32(src)
+		require.True(t, f >= 0 && f < 1, "f=%v", f)
+		buckets[int(f*bucketCount)]++
+	}
+	for i, count := range buckets {
+		requireBinomialCount(t, trials, 1.0/bucketCount, count, "i=%d", i)
+	}
+}
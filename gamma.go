@@ -0,0 +1,50 @@
+package random
+
+import "math"
+
+// gammaFloat64 returns a gamma-distributed pseudo-random float64 value with the given shape and scale 1. shape
+// must be positive. For shape < 1, the result may underflow to 0; use logGammaFloat64() if that matters.
+func gammaFloat64(src Source, shape float64) float64 {
+	if shape >= 1 {
+		return gammaMarsagliaTsang(src, shape)
+	}
+	return math.Exp(logGammaFloat64(src, shape))
+}
+
+// logGammaFloat64 returns the logarithm of a gamma-distributed pseudo-random float64 value with the given
+// shape and scale 1. shape must be positive.
+//
+// For shape < 1, this uses the fact that if X is gamma-distributed with shape a+1 and U is uniform on (0, 1],
+// then X*U^(1/a) is gamma-distributed with shape a. The latter factor can underflow for small a, so it's
+// computed in log space.
+func logGammaFloat64(src Source, shape float64) float64 {
+	if shape >= 1 {
+		return math.Log(gammaMarsagliaTsang(src, shape))
+	}
+	return math.Log(gammaMarsagliaTsang(src, shape+1)) + math.Log(openFloat64(src))/shape
+}
+
+// gammaMarsagliaTsang returns a gamma-distributed pseudo-random float64 value with the given shape, which must
+// be at least 1, and scale 1, using the method from Marsaglia and Tsang's "A Simple Method for Generating
+// Gamma Variables" (2000).
+func gammaMarsagliaTsang(src Source, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := NormFloat64(src)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := openFloat64(src)
+		// Fast acceptance using a squeeze.
+		x2 := x * x
+		if u < 1-0.0331*x2*x2 {
+			return d * v
+		}
+		if math.Log(u) < 0.5*x2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGammaFloat64Moments checks that the sample mean and variance of gammaFloat64() are both about shape,
+// for values of shape on both sides of 1.
+func TestGammaFloat64Moments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	for _, shape := range []float64{0.1, 0.5, 1, 2.5, 10, 1000} {
+		shape := shape
+		t.Run(fmt.Sprintf("shape=%v", shape), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(shape * 10))
+			mean, variance := sampleMoments(trials, func() float64 {
+				return gammaFloat64(src, shape)
+			})
+			// The standard error of the mean is sqrt(shape/trials), and the standard error of the
+			// variance is shape*sqrt((2+6/shape)/trials), since the excess kurtosis is 6/shape.
+			require.InDelta(t, shape, mean, 5*math.Sqrt(shape/trials))
+			require.InDelta(t, shape, variance, 5*shape*math.Sqrt((2+6/shape)/trials))
+		})
+	}
+}
+
+// TestLogGammaFloat64Small checks that logGammaFloat64() returns finite values for very small shapes, whose
+// gamma variates would underflow.
+func TestLogGammaFloat64Small(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for i := 0; i < 1000; i++ {
+		x := logGammaFloat64(src, 1e-3)
+		require.False(t, math.IsInf(x, 0) || math.IsNaN(x), "x=%v", x)
+	}
+}
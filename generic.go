@@ -0,0 +1,38 @@
+package random
+
+import "math"
+
+// Unsigned is a constraint that permits any unsigned integer type. It's the same as constraints.Unsigned from
+// golang.org/x/exp/constraints, which is copied here to avoid the dependency.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Bounded returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be non-zero.
+//
+// If T is at most 32 bits, this uses Uint32n(); otherwise, it uses Uint64n(). Since the result is less than n,
+// converting it back to T never truncates, even for 8- and 16-bit T.
+func Bounded[T Unsigned](src Source, n T) T {
+	if n == 0 {
+		panic("n must be non-zero in call to Bounded")
+	}
+
+	if uint64(^T(0)) <= math.MaxUint32 {
+		return T(Uint32n(src, uint32(n)))
+	}
+	return T(Uint64n(src, uint64(n)))
+}
+
+// Choice returns a uniformly-distributed element of s, which must be non-empty.
+//
+// This uses Uint32n() if len(s) fits in 32 bits, and Uint64n() otherwise.
+func Choice[T any](src Source, s []T) T {
+	if len(s) == 0 {
+		panic("s must be non-empty in call to Choice")
+	}
+
+	if uint64(len(s)) <= math.MaxUint32 {
+		return s[Uint32n(src, uint32(len(s)))]
+	}
+	return s[Uint64n(src, uint64(len(s)))]
+}
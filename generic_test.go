@@ -0,0 +1,118 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testBoundedMatches checks that Bounded() returns the same values as expectedFn for the given values of n.
+func testBoundedMatches[T Unsigned](t *testing.T, ns []T, expectedFn func(src Source, n T) T) {
+	for _, n := range ns {
+		src := rand.NewSource(int64(n))
+		expectedSrc := rand.NewSource(int64(n))
+		for i := 0; i < 100; i++ {
+			u := Bounded(src, n)
+			require.Less(t, u, n, "n=%d", n)
+			require.Equal(t, expectedFn(expectedSrc, n), u, "n=%d", n)
+		}
+	}
+}
+
+// TestBoundedMatches checks that Bounded() uses Uint32n() for T with at most 32 bits, and Uint64n() otherwise.
+func TestBoundedMatches(t *testing.T) {
+	t.Parallel()
+	var ns8 []uint8
+	for n := 1; n <= math.MaxUint8; n++ {
+		ns8 = append(ns8, uint8(n))
+	}
+	testBoundedMatches(t, ns8, func(src Source, n uint8) uint8 {
+		return uint8(Uint32n(src, uint32(n)))
+	})
+	testBoundedMatches(t, []uint16{1, 2, 3, 1000, math.MaxUint16 - 1, math.MaxUint16}, func(src Source, n uint16) uint16 {
+		return uint16(Uint32n(src, uint32(n)))
+	})
+	testBoundedMatches(t, []uint32{1, 2, 3, 1 << 20, math.MaxUint32}, Uint32n)
+	testBoundedMatches(t, []uint64{1, 2, 3, 1 << 20, 1 << 40, math.MaxUint64}, Uint64n)
+}
+
+// testBoundedUniform checks that Bounded() returns roughly uniform values for the given small n.
+func testBoundedUniform[T Unsigned](t *testing.T, n T) {
+	const trials = 100000
+	src := rand.NewSource(int64(n))
+	counts := make([]int, n)
+	for i := 0; i < trials; i++ {
+		counts[Bounded(src, n)]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1/float64(n), count, "n=%d i=%d", n, i)
+	}
+}
+
+// TestBoundedUniform checks that Bounded() returns roughly uniform values for various types.
+func TestBoundedUniform(t *testing.T) {
+	t.Parallel()
+	testBoundedUniform(t, uint8(7))
+	testBoundedUniform(t, uint16(10))
+	testBoundedUniform(t, uint32(13))
+	testBoundedUniform(t, uint64(17))
+	testBoundedUniform(t, uint(19))
+}
+
+// TestBoundedZero checks that Bounded() panics for n == 0.
+func TestBoundedZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Bounded", func() {
+		Bounded(&src, uint8(0))
+	})
+}
+
+// TestChoiceUniform checks that over many draws, each element is returned by Choice() with frequency about
+// 1/len(s).
+func TestChoiceUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	s := []string{"a", "b", "c", "d", "e"}
+	src := rand.NewSource(1)
+	counts := make(map[string]int)
+	for i := 0; i < trials; i++ {
+		counts[Choice(src, s)]++
+	}
+	for _, e := range s {
+		requireBinomialCount(t, trials, 1/float64(len(s)), counts[e], "e=%s", e)
+	}
+}
+
+// TestChoiceMatchesUint32n checks that Choice() indexes s with Uint32n().
+func TestChoiceMatchesUint32n(t *testing.T) {
+	t.Parallel()
+	s := []int{10, 11, 12, 13, 14, 15, 16}
+	src := rand.NewSource(2)
+	expectedSrc := rand.NewSource(2)
+	for i := 0; i < 100; i++ {
+		require.Equal(t, s[Uint32n(expectedSrc, uint32(len(s)))], Choice(src, s))
+	}
+}
+
+// TestChoiceSingle checks that Choice() on a single-element slice always returns that element, with exactly
+// one call to Int63().
+func TestChoiceSingle(t *testing.T) {
+	t.Parallel()
+	for _, v := range []uint32{0, 0x80000000, 0xffffffff} {
+		src := makeTestSource(0, v)
+		require.Equal(t, "x", Choice(&src, []string{"x"}))
+		require.Equal(t, 1, src.callCount)
+	}
+}
+
+// TestChoiceEmpty checks that Choice() panics for an empty slice.
+func TestChoiceEmpty(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "s must be non-empty in call to Choice", func() {
+		Choice(&src, []int{})
+	})
+}
@@ -0,0 +1,51 @@
+package random
+
+// A Halton generates the Halton low-discrepancy sequence, for quasi-Monte Carlo methods. Unlike the other
+// generators in this package, it's completely deterministic, and doesn't use a Source; its points are spread
+// out more evenly than pseudo-random points, so integrals estimated with them converge faster.
+//
+// The ith point (starting from i == 1) has as its coordinate for base b the van der Corput radical inverse of
+// i in base b, i.e. the digits of i in base b mirrored around the radix point. For example, for base 2, the
+// coordinates are 1/2, 1/4, 3/4, 1/8, 5/8, ....
+//
+// A Halton is not safe for concurrent use by multiple goroutines.
+type Halton struct {
+	bases []int
+	i     uint64
+}
+
+// NewHalton returns a new Halton whose points have one coordinate per element of bases, computed in that
+// base. Each base must be at least 2, and for the sequence to be useful the bases should be pairwise
+// coprime; the usual choice is the first len(bases) primes.
+func NewHalton(bases []int) *Halton {
+	for _, b := range bases {
+		if b < 2 {
+			panic("bases must be at least 2 in call to NewHalton")
+		}
+	}
+
+	return &Halton{bases: append([]int(nil), bases...)}
+}
+
+// Next returns the next point of the sequence, which is in [0, 1)^d, where d is the number of bases.
+func (h *Halton) Next() []float64 {
+	h.i++
+	point := make([]float64, len(h.bases))
+	for j, b := range h.bases {
+		point[j] = radicalInverse(h.i, uint64(b))
+	}
+	return point
+}
+
+// radicalInverse returns the van der Corput radical inverse of i in base b.
+func radicalInverse(i, b uint64) float64 {
+	invB := 1 / float64(b)
+	f := invB
+	var r float64
+	for i > 0 {
+		r += f * float64(i%b)
+		i /= b
+		f *= invB
+	}
+	return r
+}
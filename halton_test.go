@@ -0,0 +1,76 @@
+package random
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHaltonBase2 checks the first few points of the van der Corput sequence in base 2 exactly.
+func TestHaltonBase2(t *testing.T) {
+	t.Parallel()
+	h := NewHalton([]int{2})
+	expected := []float64{0.5, 0.25, 0.75, 0.125, 0.625, 0.375, 0.875, 0.0625}
+	for i, v := range expected {
+		require.Equal(t, []float64{v}, h.Next(), "i=%d", i)
+	}
+}
+
+// TestHaltonBases checks the first few points of the two-dimensional Halton sequence with bases 2 and 3.
+func TestHaltonBases(t *testing.T) {
+	t.Parallel()
+	h := NewHalton([]int{2, 3})
+	expected := [][]float64{
+		{1.0 / 2, 1.0 / 3},
+		{1.0 / 4, 2.0 / 3},
+		{3.0 / 4, 1.0 / 9},
+		{1.0 / 8, 4.0 / 9},
+		{5.0 / 8, 7.0 / 9},
+		{3.0 / 8, 2.0 / 9},
+	}
+	for i, point := range expected {
+		actual := h.Next()
+		require.Equal(t, len(point), len(actual))
+		for j := range point {
+			require.InDelta(t, point[j], actual[j], 1e-15, "i=%d j=%d", i, j)
+		}
+	}
+}
+
+// TestHaltonStratified checks that the first b³ points of the van der Corput sequence in base b are exactly
+// the multiples of 1/b³ (up to rounding), each appearing once.
+func TestHaltonStratified(t *testing.T) {
+	t.Parallel()
+	for _, b := range []int{2, 3, 5, 7} {
+		n := b * b * b
+		h := NewHalton([]int{b})
+		seen := make([]bool, n)
+		// The sequence starts from i == 1, so also count 0 as the point for i == 0.
+		seen[0] = true
+		for i := 1; i < n; i++ {
+			m := int(math.Round(h.Next()[0] * float64(n)))
+			require.False(t, seen[m], "b=%d i=%d m=%d", b, i, m)
+			seen[m] = true
+		}
+	}
+}
+
+// TestHaltonCopiesBases checks that NewHalton() makes its own copy of bases.
+func TestHaltonCopiesBases(t *testing.T) {
+	t.Parallel()
+	bases := []int{2}
+	h := NewHalton(bases)
+	bases[0] = 3
+	require.Equal(t, []float64{0.5}, h.Next())
+}
+
+// TestHaltonInvalid checks that NewHalton() panics for bases less than 2.
+func TestHaltonInvalid(t *testing.T) {
+	t.Parallel()
+	for _, b := range []int{-1, 0, 1} {
+		require.PanicsWithValue(t, "bases must be at least 2 in call to NewHalton", func() {
+			NewHalton([]int{2, b})
+		}, "b=%d", b)
+	}
+}
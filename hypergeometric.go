@@ -0,0 +1,133 @@
+package random
+
+import "math"
+
+// hypergeometricHRUAThreshold is the value of draws above which Hypergeometric() switches from the inversion
+// method to the HRUA method.
+const hypergeometricHRUAThreshold = 10
+
+// Hypergeometric returns a hypergeometrically-distributed pseudo-random int, i.e. the number of marked items
+// among draws items picked without replacement from a population of items, successes of which are marked.
+// successes and draws must both be in the range 0 to population (inclusive).
+//
+// For draws <= 10, this simulates the draws one at a time, which takes O(draws) time. For larger draws, this
+// uses Stadlober's ratio-of-uniforms method (HRUA) from "The ratio of uniforms approach for generating discrete
+// random variates" (1990), which takes a small constant expected number of calls to Float64(). Both methods
+// follow NumPy's implementations.
+//
+// If the result is determined by the parameters (e.g., if draws or successes is 0, or draws is population),
+// this returns it without using any randomness.
+func Hypergeometric(src Source, population, successes, draws int) int {
+	if population < 0 {
+		panic("population must be non-negative in call to Hypergeometric")
+	}
+	if successes < 0 || successes > population {
+		panic("successes must be in [0, population] in call to Hypergeometric")
+	}
+	if draws < 0 || draws > population {
+		panic("draws must be in [0, population] in call to Hypergeometric")
+	}
+
+	failures := population - successes
+	switch {
+	case draws == 0 || successes == 0:
+		return 0
+	case failures == 0:
+		return draws
+	case draws == population:
+		return successes
+	}
+
+	if draws > hypergeometricHRUAThreshold {
+		return hypergeometricHRUA(src, successes, failures, draws)
+	}
+	return hypergeometricInversion(src, successes, failures, draws)
+}
+
+// hypergeometricInversion draws the items one at a time, keeping track of how many of the rarer kind are
+// left.
+func hypergeometricInversion(src Source, good, bad, sample int) int {
+	d1 := float64(bad + good - sample)
+	d2 := math.Min(float64(bad), float64(good))
+
+	y := d2
+	k := float64(sample)
+	for y > 0 {
+		y -= math.Floor(Float64(src) + y/(d1+k))
+		k--
+		if k == 0 {
+			break
+		}
+	}
+
+	z := int(d2 - y)
+	if good > bad {
+		return sample - z
+	}
+	return z
+}
+
+// Constants for hypergeometricHRUA(): hruaD1 is 2*sqrt(2/e), and hruaD2 is 3 - 2*sqrt(3/e).
+const (
+	hruaD1 = 1.7155277699214135
+	hruaD2 = 0.8989161620588988
+)
+
+// hypergeometricHRUA works with the rarer kind of item and the smaller of the sample and its complement, and
+// then maps the result back.
+func hypergeometricHRUA(src Source, good, bad, sample int) int {
+	minGoodBad := math.Min(float64(good), float64(bad))
+	maxGoodBad := math.Max(float64(good), float64(bad))
+	popSize := float64(good + bad)
+	m := math.Min(float64(sample), popSize-float64(sample))
+
+	d4 := minGoodBad / popSize
+	d5 := 1 - d4
+	d6 := m*d4 + 0.5
+	d7 := math.Sqrt((popSize-m)*float64(sample)*d4*d5/(popSize-1) + 0.5)
+	d8 := hruaD1*d7 + hruaD2
+	d9 := math.Floor((m + 1) * (minGoodBad + 1) / (popSize + 2))
+	logWeight := func(z float64) float64 {
+		return lgamma(z+1) + lgamma(minGoodBad-z+1) + lgamma(m-z+1) + lgamma(maxGoodBad-m+z+1)
+	}
+	d10 := logWeight(d9)
+	d11 := math.Min(math.Min(m, minGoodBad)+1, math.Floor(d6+16*d7))
+
+	var z float64
+	for {
+		x := openFloat64(src)
+		y := Float64(src)
+		w := d6 + d8*(y-0.5)/x
+		if w < 0 || w >= d11 {
+			continue
+		}
+
+		z = math.Floor(w)
+		t := d10 - logWeight(z)
+		// Fast acceptance.
+		if x*(4-x)-3 <= t {
+			break
+		}
+		// Fast rejection.
+		if x*(x-t) >= 1 {
+			continue
+		}
+		if 2*math.Log(x) <= t {
+			break
+		}
+	}
+
+	if good > bad {
+		z = m - z
+	}
+	if m < float64(sample) {
+		z = float64(good) - z
+	}
+	return int(z)
+}
+
+// lgamma returns log(|Γ(x)|).
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
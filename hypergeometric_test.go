@@ -0,0 +1,105 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHypergeometricMoments checks that the sample mean and variance of Hypergeometric() are about
+// draws*K/N and draws*(K/N)*(1-K/N)*(N-draws)/(N-1), for parameters on both sides of
+// hypergeometricHRUAThreshold and with either kind of item being rarer.
+func TestHypergeometricMoments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct{ population, successes, draws int }{
+		{20, 7, 5},
+		{20, 13, 10},
+		{100, 30, 11},
+		{100, 70, 60},
+		{1000, 10, 500},
+		{10000, 5000, 990},
+		{1000000, 300000, 200000},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("N=%d,K=%d,n=%d", tc.population, tc.successes, tc.draws), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(tc.population + tc.successes + tc.draws))
+			mean, variance := sampleMoments(trials, func() float64 {
+				v := Hypergeometric(src, tc.population, tc.successes, tc.draws)
+				require.True(t, v >= 0 && v <= tc.draws && v <= tc.successes, "v=%d", v)
+				return float64(v)
+			})
+			n := float64(tc.population)
+			p := float64(tc.successes) / n
+			d := float64(tc.draws)
+			expectedMean := d * p
+			expectedVariance := d * p * (1 - p) * (n - d) / (n - 1)
+			require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials))
+			require.InDelta(t, expectedVariance, variance, 5*expectedVariance*math.Sqrt(3.0/trials))
+		})
+	}
+}
+
+// TestHypergeometricProbabilities checks that the fraction of Hypergeometric() values equal to each k is about
+// right, for parameters on both sides of hypergeometricHRUAThreshold.
+func TestHypergeometricProbabilities(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct{ population, successes, draws int }{
+		{20, 8, 6},
+		{60, 25, 30},
+	}
+	for _, tc := range testCases {
+		src := rand.NewSource(int64(tc.draws))
+		counts := make(map[int]int)
+		for i := 0; i < trials; i++ {
+			counts[Hypergeometric(src, tc.population, tc.successes, tc.draws)]++
+		}
+		logChoose := func(n, k int) float64 {
+			return lgamma(float64(n+1)) - lgamma(float64(k+1)) - lgamma(float64(n-k+1))
+		}
+		mode := tc.draws * tc.successes / tc.population
+		for k := mode - 2; k <= mode+2; k++ {
+			p := math.Exp(logChoose(tc.successes, k) + logChoose(tc.population-tc.successes, tc.draws-k) -
+				logChoose(tc.population, tc.draws))
+			requireBinomialCount(t, trials, p, counts[k], "tc=%v k=%d", tc, k)
+		}
+	}
+}
+
+// TestHypergeometricTrivial checks that Hypergeometric() returns the result without using any randomness when
+// it's determined by the parameters.
+func TestHypergeometricTrivial(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, 0, Hypergeometric(&src, 0, 0, 0))
+	require.Equal(t, 0, Hypergeometric(&src, 10, 5, 0))
+	require.Equal(t, 0, Hypergeometric(&src, 10, 0, 5))
+	require.Equal(t, 5, Hypergeometric(&src, 10, 10, 5))
+	require.Equal(t, 3, Hypergeometric(&src, 10, 3, 10))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestHypergeometricInvalid checks that Hypergeometric() panics for invalid parameters.
+func TestHypergeometricInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "population must be non-negative in call to Hypergeometric", func() {
+		Hypergeometric(&src, -1, 0, 0)
+	})
+	for _, successes := range []int{-1, 11} {
+		require.PanicsWithValue(t, "successes must be in [0, population] in call to Hypergeometric", func() {
+			Hypergeometric(&src, 10, successes, 5)
+		}, "successes=%d", successes)
+	}
+	for _, draws := range []int{-1, 11} {
+		require.PanicsWithValue(t, "draws must be in [0, population] in call to Hypergeometric", func() {
+			Hypergeometric(&src, 10, 5, draws)
+		}, "draws=%d", draws)
+	}
+}
@@ -0,0 +1,69 @@
+package random
+
+import "math/bits"
+
+// MultiIndex fills in out with a uniformly-distributed index into an N-dimensional array with the given
+// dimensions, i.e. it sets out[i] to a uniformly-distributed number in the range 0 to dims[i]-1 (inclusive),
+// as if by Uint32n(src, dims[i]). len(out) must equal len(dims), and each dimension must be non-zero.
+//
+// When a dimension is the same as the previous one (e.g., for a square matrix), the threshold computation
+// from Uint32n() is shared between them, as with Bounded32. Only runs of equal adjacent dimensions share it,
+// so e.g. dims of {3, 4, 3} compute it separately for each 3. Either way, the values are the same as those
+// from Uint32n().
+func MultiIndex(src Source, dims []uint32, out []uint32) {
+	if len(out) != len(dims) {
+		panic("len(out) must equal len(dims) in call to MultiIndex")
+	}
+	checkDims(dims, "MultiIndex")
+
+	var b Bounded32
+	for i := range dims {
+		out[i] = nextIndex(src, dims, i, &b)
+	}
+}
+
+// FlatIndex returns the row-major offset of a uniformly-distributed index into an N-dimensional array with the
+// given dimensions, i.e. the offset of the index that MultiIndex() would return for the same state of src.
+// Each dimension must be non-zero, and the product of the dimensions must fit in a uint64. If dims is empty,
+// this returns 0.
+func FlatIndex(src Source, dims []uint32) uint64 {
+	checkDims(dims, "FlatIndex")
+	size := uint64(1)
+	for _, n := range dims {
+		var hi uint64
+		hi, size = bits.Mul64(size, uint64(n))
+		if hi != 0 {
+			panic("product of dims must fit in a uint64 in call to FlatIndex")
+		}
+	}
+
+	var b Bounded32
+	var offset uint64
+	for i, n := range dims {
+		offset = offset*uint64(n) + uint64(nextIndex(src, dims, i, &b))
+	}
+	return offset
+}
+
+// checkDims panics if any of dims is zero. funcName is the name of the calling function, for the panic
+// message.
+func checkDims(dims []uint32, funcName string) {
+	for _, n := range dims {
+		if n == 0 {
+			panic("dims must be non-zero in call to " + funcName)
+		}
+	}
+}
+
+// nextIndex returns a uniformly-distributed number in the range 0 to dims[i]-1 (inclusive). If dims[i] is the
+// same as dims[i-1], it uses *b, (re)initializing it if necessary.
+func nextIndex(src Source, dims []uint32, i int, b *Bounded32) uint32 {
+	n := dims[i]
+	if i == 0 || dims[i-1] != n {
+		return Uint32n(src, n)
+	}
+	if b.N() != n {
+		*b = NewBounded32(n)
+	}
+	return b.Next(src)
+}
@@ -0,0 +1,84 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMultiIndexMatchesUint32n checks that MultiIndex() returns the same values as calling Uint32n() for each
+// dimension, including repeated ones.
+func TestMultiIndexMatchesUint32n(t *testing.T) {
+	t.Parallel()
+	dims := []uint32{3, 3, 3, 7, 1, 1000, 1000, 1 << 31, 0x80000001, 0x80000001}
+	src1 := rand.NewSource(1)
+	src2 := rand.NewSource(1)
+	out := make([]uint32, len(dims))
+	for i := 0; i < 1000; i++ {
+		MultiIndex(src1, dims, out)
+		for j, n := range dims {
+			require.Equal(t, Uint32n(src2, n), out[j], "i=%d j=%d", i, j)
+		}
+	}
+}
+
+// TestFlatIndexMatchesMultiIndex checks that FlatIndex() returns the row-major offset of the index returned by
+// MultiIndex().
+func TestFlatIndexMatchesMultiIndex(t *testing.T) {
+	t.Parallel()
+	dims := []uint32{4, 4, 5, 2, 2, 2}
+	src1 := rand.NewSource(1)
+	src2 := rand.NewSource(1)
+	out := make([]uint32, len(dims))
+	for i := 0; i < 1000; i++ {
+		MultiIndex(src1, dims, out)
+		var expected uint64
+		for j, n := range dims {
+			expected = expected*uint64(n) + uint64(out[j])
+		}
+		require.Equal(t, expected, FlatIndex(src2, dims), "i=%d", i)
+	}
+}
+
+// TestFlatIndexUniform checks that FlatIndex() returns roughly uniform offsets.
+func TestFlatIndexUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	dims := []uint32{3, 3, 4}
+	src := rand.NewSource(2)
+	var counts [3 * 3 * 4]int
+	for i := 0; i < trials; i++ {
+		counts[FlatIndex(src, dims)]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1/float64(len(counts)), count, "i=%d", i)
+	}
+}
+
+// TestFlatIndexEmpty checks that FlatIndex() returns 0 without using any randomness for empty dims.
+func TestFlatIndexEmpty(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, uint64(0), FlatIndex(&src, nil))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestMultiIndexInvalid checks that MultiIndex() and FlatIndex() panic for invalid arguments.
+func TestMultiIndexInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "len(out) must equal len(dims) in call to MultiIndex", func() {
+		MultiIndex(&src, []uint32{1, 2}, make([]uint32, 1))
+	})
+	require.PanicsWithValue(t, "dims must be non-zero in call to MultiIndex", func() {
+		MultiIndex(&src, []uint32{1, 0}, make([]uint32, 2))
+	})
+	require.PanicsWithValue(t, "dims must be non-zero in call to FlatIndex", func() {
+		FlatIndex(&src, []uint32{0, 1})
+	})
+	require.PanicsWithValue(t, "product of dims must fit in a uint64 in call to FlatIndex", func() {
+		FlatIndex(&src, []uint32{math.MaxUint32, math.MaxUint32, 2})
+	})
+}
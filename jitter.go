@@ -0,0 +1,31 @@
+package random
+
+import "time"
+
+// Jitter returns a pseudo-random delay for retry number attempt (starting from 0) using exponential backoff
+// with "full jitter", i.e. a uniformly-distributed duration in the range 0 to min(cap, base*2^attempt)
+// (inclusive), as described in https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ .
+// base and cap must be non-negative, and attempt must be non-negative.
+//
+// base*2^attempt is never computed directly, since it overflows for large attempt; instead, the upper bound is
+// clamped to cap as soon as base exceeds cap/2^attempt. The delay is then drawn with Uint64n(), so it's exactly
+// uniform over the nanoseconds in the range.
+func Jitter(src Source, base, cap time.Duration, attempt int) time.Duration {
+	if base < 0 {
+		panic("base must be non-negative in call to Jitter")
+	}
+	if cap < 0 {
+		panic("cap must be non-negative in call to Jitter")
+	}
+	if attempt < 0 {
+		panic("attempt must be non-negative in call to Jitter")
+	}
+
+	upper := cap
+	// If attempt >= 63, then cap>>attempt is 0, so any positive base is clamped.
+	if base <= cap>>uint(attempt) {
+		upper = base << uint(attempt)
+	}
+	// upper+1 fits in a uint64, even if upper is the largest time.Duration.
+	return time.Duration(Uint64n(src, uint64(upper)+1))
+}
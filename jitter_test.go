@@ -0,0 +1,73 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestJitterBounds checks that Jitter() stays within [0, min(cap, base*2^attempt)], and gets close to the
+// upper bound, which doubles with each attempt until it's capped.
+func TestJitterBounds(t *testing.T) {
+	t.Parallel()
+	const base = 10 * time.Millisecond
+	const cap = time.Second
+	src := rand.NewSource(1)
+	for attempt := 0; attempt < 70; attempt++ {
+		upper := cap
+		if attempt < 7 {
+			upper = base << attempt
+		}
+		var max time.Duration
+		for i := 0; i < 1000; i++ {
+			d := Jitter(src, base, cap, attempt)
+			require.True(t, d >= 0 && d <= upper, "attempt=%d d=%v", attempt, d)
+			if d > max {
+				max = d
+			}
+		}
+		require.Greater(t, max, upper*99/100, "attempt=%d", attempt)
+	}
+}
+
+// TestJitterUpperBound checks that Jitter() can return exactly the upper bound, including for the largest
+// possible cap.
+func TestJitterUpperBound(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		base, cap time.Duration
+		attempt   int
+		upper     time.Duration
+	}{
+		{time.Second, time.Minute, 0, time.Second},
+		{time.Second, time.Minute, 5, 32 * time.Second},
+		{time.Second, time.Minute, 6, time.Minute},
+		{time.Second, time.Minute, 1000, time.Minute},
+		{1, math.MaxInt64, 62, 1 << 62},
+		{1, math.MaxInt64, 63, math.MaxInt64},
+		{0, time.Minute, 1000, 0},
+	}
+	for _, tc := range testCases {
+		src := testUint64Source{vs: []uint64{math.MaxUint64}}
+		require.Equal(t, tc.upper, Jitter(&src, tc.base, tc.cap, tc.attempt),
+			"base=%v cap=%v attempt=%d", tc.base, tc.cap, tc.attempt)
+	}
+}
+
+// TestJitterInvalid checks that Jitter() panics for negative arguments.
+func TestJitterInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "base must be non-negative in call to Jitter", func() {
+		Jitter(&src, -1, time.Second, 0)
+	})
+	require.PanicsWithValue(t, "cap must be non-negative in call to Jitter", func() {
+		Jitter(&src, time.Second, -1, 0)
+	})
+	require.PanicsWithValue(t, "attempt must be non-negative in call to Jitter", func() {
+		Jitter(&src, time.Second, time.Second, -1)
+	})
+}
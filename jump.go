@@ -0,0 +1,48 @@
+package random
+
+// A Cloneable is a Source whose state can be copied, e.g. to snapshot a simulation before a risky branch and
+// restore it later by cloning the snapshot again. SplitMix64, PCG, and Xoshiro256 implement Cloneable.
+type Cloneable interface {
+	Source
+	// Clone returns an independent copy of the Cloneable with the same state, which returns the same
+	// values as the original from then on.
+	Clone() Source
+}
+
+// A Jumpable is a Cloneable that can jump ahead by a fixed, large number of values. This can be used to split a
+// single stream into non-overlapping substreams, e.g. for parallel simulations. PCG and Xoshiro256 implement
+// Jumpable.
+type Jumpable interface {
+	Cloneable
+	// Jump advances the Jumpable as if a fixed, large number of values had been generated.
+	Jump()
+}
+
+// SplitStreams returns count independent Sources, which are clones of src with its state advanced by 0, 1, ...,
+// count-1 jumps. src is left advanced by count jumps, so it can be used as another independent Source, or
+// passed to SplitStreams() again. count must be non-negative.
+func SplitStreams(src Jumpable, count int) []Source {
+	if count < 0 {
+		panic("count must be non-negative in call to SplitStreams")
+	}
+
+	streams := make([]Source, count)
+	for i := range streams {
+		streams[i] = src.Clone()
+		src.Jump()
+	}
+	return streams
+}
+
+// DeriveSource returns a new Source that's deterministically derived from the next value of parent and id, so
+// the same parent state and id always give the same child stream, and different ids give independent child
+// streams. This is useful for hierarchical simulations, where each component gets its own stream identified by
+// a path or name hashed into id. parent is advanced by one call to Uint64() (or two calls to Int63(), if it
+// isn't a Source64).
+//
+// The child is a Xoshiro256 seeded with the parent's value XORed with the SplitMix64 hash of id. Since
+// NewXoshiro256() runs its seed through SplitMix64 again, even ids and parent values that differ by a single
+// bit give unrelated children.
+func DeriveSource(parent Source, id uint64) Source {
+	return NewXoshiro256(randUint64(parent) ^ splitMix64(&id))
+}
@@ -0,0 +1,138 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestClone checks that a clone of each Cloneable source returns the same values as the original, and that
+// advancing one doesn't affect the other.
+func TestClone(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		newSrc func() Cloneable
+	}{
+		{"SplitMix64", func() Cloneable { return NewSplitMix64(1) }},
+		{"PCG", func() Cloneable { return NewPCG(42, 54) }},
+		{"Xoshiro256", func() Cloneable { return NewXoshiro256(1) }},
+	}
+	for _, tc := range testCases {
+		src := tc.newSrc()
+		for i := 0; i < 10; i++ {
+			src.Int63()
+		}
+		clone := src.Clone()
+		var expected []int64
+		for i := 0; i < 100; i++ {
+			expected = append(expected, src.Int63())
+		}
+		// Advancing the original shouldn't have affected the clone.
+		for i := 0; i < 100; i++ {
+			require.Equal(t, expected[i], clone.Int63(), "%s i=%d", tc.name, i)
+		}
+
+		// Conversely, advancing a clone shouldn't affect the original.
+		want := src.Clone().Int63()
+		snapshot := src.Clone()
+		for i := 0; i < 10; i++ {
+			snapshot.Int63()
+		}
+		require.Equal(t, want, src.Int63(), tc.name)
+	}
+}
+
+// testSplitStreamsDisjoint checks that streams returned by SplitStreams() don't produce any common values
+// within a large window.
+func testSplitStreamsDisjoint(t *testing.T, src Jumpable) {
+	const window = 100000
+	streams := SplitStreams(src, 3)
+	require.Equal(t, 3, len(streams))
+	// Also check src itself, which should be advanced past the other streams.
+	streams = append(streams, src)
+
+	seen := make(map[int64]int)
+	for i, stream := range streams {
+		for j := 0; j < window; j++ {
+			v := stream.Int63()
+			prev, ok := seen[v]
+			require.False(t, ok, "stream %d and stream %d both returned %d", prev, i, v)
+			seen[v] = i
+		}
+	}
+}
+
+// TestSplitStreamsDisjoint checks that split streams don't overlap for each Jumpable source.
+func TestSplitStreamsDisjoint(t *testing.T) {
+	t.Parallel()
+	testSplitStreamsDisjoint(t, NewPCG(42, 54))
+	testSplitStreamsDisjoint(t, NewXoshiro256(1))
+}
+
+// TestSplitStreamsJumps checks that the ith stream returned by SplitStreams() is a clone of the source
+// advanced by i jumps.
+func TestSplitStreamsJumps(t *testing.T) {
+	t.Parallel()
+	streams := SplitStreams(NewXoshiro256(2), 3)
+	expected := NewXoshiro256(2)
+	for i, stream := range streams {
+		require.Equal(t, *expected, *stream.(*Xoshiro256), "i=%d", i)
+		expected.Jump()
+	}
+}
+
+// TestSplitStreamsNegative checks that SplitStreams() panics for negative count.
+func TestSplitStreamsNegative(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "count must be non-negative in call to SplitStreams", func() {
+		SplitStreams(NewPCG(1, 1), -1)
+	})
+}
+
+// TestDeriveSourceDeterministic checks that DeriveSource() returns the same child stream for the same parent
+// state and id.
+func TestDeriveSourceDeterministic(t *testing.T) {
+	t.Parallel()
+	for _, id := range []uint64{0, 1, 12345} {
+		child1 := DeriveSource(NewPCG(42, 54), id)
+		child2 := DeriveSource(NewPCG(42, 54), id)
+		for i := 0; i < 100; i++ {
+			require.Equal(t, child1.Int63(), child2.Int63(), "id=%d i=%d", id, i)
+		}
+	}
+}
+
+// TestDeriveSourceDiverges checks that DeriveSource() returns unrelated child streams for different ids, and
+// for the same id with different parent states.
+func TestDeriveSourceDiverges(t *testing.T) {
+	t.Parallel()
+	const n = 1000
+	parent := NewPCG(42, 54)
+	var children []Source
+	for id := uint64(0); id < 4; id++ {
+		children = append(children, DeriveSource(NewPCG(42, 54), id))
+	}
+	// The parent has advanced, so this child should differ from the one for id 0 above.
+	DeriveSource(parent, 0)
+	children = append(children, DeriveSource(parent, 0))
+
+	draws := make([][]uint32, len(children))
+	for i, child := range children {
+		for j := 0; j < 100; j++ {
+			draws[i] = append(draws[i], Uint32n(child, n))
+		}
+	}
+	for i := range draws {
+		for j := i + 1; j < len(draws); j++ {
+			matches := 0
+			for k := range draws[i] {
+				if draws[i][k] == draws[j][k] {
+					matches++
+				}
+			}
+			// Each value matches with probability 1/n.
+			require.LessOrEqual(t, matches, 3, "i=%d j=%d", i, j)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package random
+
+// Parameters of MT19937-64.
+const (
+	mt64N         = 312
+	mt64M         = 156
+	mt64MatrixA   = 0xb5026f5aa96619e9
+	mt64UpperMask = 0xffffffff80000000
+	mt64LowerMask = 0x7fffffff
+)
+
+// An MT19937_64 is a Source64 that implements the 64-bit Mersenne Twister MT19937-64 from Matsumoto and
+// Nishimura, as given at http://www.math.sci.hiroshima-u.ac.jp/m-mat/MT/emt64.html . Its output is identical
+// to that of std::mt19937_64 in C++ (and other standard implementations) for the same seed, so it's useful
+// for reproducing results from other languages.
+//
+// Each call to Uint64() returns one MT19937-64 output, and Int63() returns its top 63 bits.
+//
+// An MT19937_64 is not safe for concurrent use by multiple goroutines.
+type MT19937_64 struct {
+	mt  [mt64N]uint64
+	mti int
+}
+
+// NewMT19937_64 returns a new MT19937_64 initialized with the given seed, like init_genrand64() in the
+// reference implementation or the std::mt19937_64 constructor. The default seed used by both is 5489.
+func NewMT19937_64(seed uint64) *MT19937_64 {
+	m := &MT19937_64{mti: mt64N}
+	m.mt[0] = seed
+	for i := 1; i < mt64N; i++ {
+		prev := m.mt[i-1]
+		m.mt[i] = 6364136223846793005*(prev^(prev>>62)) + uint64(i)
+	}
+	return m
+}
+
+// twist regenerates all mt64N words of state at once.
+func (m *MT19937_64) twist() {
+	mag01 := [2]uint64{0, mt64MatrixA}
+	for i := 0; i < mt64N; i++ {
+		x := (m.mt[i] & mt64UpperMask) | (m.mt[(i+1)%mt64N] & mt64LowerMask)
+		m.mt[i] = m.mt[(i+mt64M)%mt64N] ^ (x >> 1) ^ mag01[x&1]
+	}
+	m.mti = 0
+}
+
+// Uint64 returns the next MT19937-64 output, which is uniformly distributed in the range 0 to 2⁶⁴-1
+// (inclusive).
+func (m *MT19937_64) Uint64() uint64 {
+	if m.mti >= mt64N {
+		m.twist()
+	}
+
+	x := m.mt[m.mti]
+	m.mti++
+
+	// Temper the raw state word.
+	x ^= (x >> 29) & 0x5555555555555555
+	x ^= (x << 17) & 0x71d67fffeda60000
+	x ^= (x << 37) & 0xfff7eee000000000
+	x ^= x >> 43
+	return x
+}
+
+// Int63 returns the top 63 bits of m.Uint64().
+func (m *MT19937_64) Int63() int64 {
+	return int64(m.Uint64() >> 1)
+}
@@ -0,0 +1,64 @@
+package random
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readUint64s reads one decimal uint64 per line from the given file.
+func readUint64s(t *testing.T, path string) []uint64 {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var vs []uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		v, err := strconv.ParseUint(scanner.Text(), 10, 64)
+		require.NoError(t, err)
+		vs = append(vs, v)
+	}
+	require.NoError(t, scanner.Err())
+	return vs
+}
+
+// TestMT19937_64Reference checks the first 1000 outputs of MT19937_64 for a few seeds against those of
+// std::mt19937_64, which are stored in testdata.
+func TestMT19937_64Reference(t *testing.T) {
+	t.Parallel()
+	for _, seed := range []uint64{5489, 1, 0x123456789abcdef} {
+		expected := readUint64s(t, fmt.Sprintf("testdata/mt19937_64_seed_%d.txt", seed))
+		require.Equal(t, 1000, len(expected))
+		m := NewMT19937_64(seed)
+		for i, v := range expected {
+			require.Equal(t, v, m.Uint64(), "seed=%d i=%d", seed, i)
+		}
+	}
+}
+
+// TestMT19937_64TenThousandth checks that the 10000th output of MT19937_64 seeded with 5489 is
+// 9981545732273789042, which is required by the C++ standard for std::mt19937_64.
+func TestMT19937_64TenThousandth(t *testing.T) {
+	t.Parallel()
+	m := NewMT19937_64(5489)
+	require.Equal(t, uint64(14514284786278117030), m.Uint64())
+	for i := 1; i < 9999; i++ {
+		m.Uint64()
+	}
+	require.Equal(t, uint64(9981545732273789042), m.Uint64())
+}
+
+// TestMT19937_64Int63 checks that Int63() returns the top 63 bits of Uint64().
+func TestMT19937_64Int63(t *testing.T) {
+	t.Parallel()
+	m1 := NewMT19937_64(5489)
+	m2 := NewMT19937_64(5489)
+	for i := 0; i < 1000; i++ {
+		require.Equal(t, int64(m1.Uint64()>>1), m2.Int63())
+	}
+}
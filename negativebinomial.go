@@ -0,0 +1,48 @@
+package random
+
+import "math"
+
+// negativeBinomialMinP is the smallest p that NegativeBinomial() accepts, which keeps the scale (1-p)/p of the
+// gamma distribution below 2²⁰.
+const negativeBinomialMinP = 1.0 / (1 << 20)
+
+// negativeBinomialMaxMean is the largest mean r(1-p)/p that NegativeBinomial() accepts.
+const negativeBinomialMaxMean = 1 << 20
+
+// negativeBinomialMaxLambda is the value that NegativeBinomial() clamps the gamma-distributed mean to before
+// passing it to Poisson(), so that the result always fits in an int, even on 32-bit platforms.
+const negativeBinomialMaxLambda = 1 << 30
+
+// NegativeBinomial returns a negative-binomially-distributed pseudo-random int, i.e. the number of failures
+// before the rth success in independent trials that each succeed with probability p. r must be positive and
+// finite, but needn't be an integer, p must be in the range 2⁻²⁰ to 1.0 (inclusive), and the mean r(1-p)/p
+// must be at most 2²⁰.
+//
+// This uses the gamma–Poisson mixture representation: if λ is gamma-distributed with shape r and scale
+// (1-p)/p, then a Poisson-distributed value with mean λ is negative-binomially distributed with parameters r
+// and p. For p == 1, this just returns 0 without using any randomness.
+//
+// λ is clamped to 2³⁰ so that the result fits in an int, but within the limits above, λ exceeds 2³⁰ only if
+// the gamma-distributed value is more than 2¹⁰ times both 1 and r, which has negligible probability (less
+// than e⁻¹⁰⁰⁰).
+func NegativeBinomial(src Source, r, p float64) int {
+	if !(r > 0) || math.IsInf(r, 1) {
+		panic("r must be positive and finite in call to NegativeBinomial")
+	}
+	if !(p >= negativeBinomialMinP && p <= 1) {
+		panic("p must be in [2⁻²⁰, 1] in call to NegativeBinomial")
+	}
+	if r*(1-p)/p > negativeBinomialMaxMean {
+		panic("r(1-p)/p must be at most 2²⁰ in call to NegativeBinomial")
+	}
+
+	if p == 1 {
+		return 0
+	}
+
+	lambda := gammaFloat64(src, r) * (1 - p) / p
+	if lambda > negativeBinomialMaxLambda {
+		lambda = negativeBinomialMaxLambda
+	}
+	return Poisson(src, lambda)
+}
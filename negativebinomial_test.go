@@ -0,0 +1,114 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNegativeBinomialMoments checks that the sample mean and variance of NegativeBinomial() are about
+// r(1-p)/p and r(1-p)/p², for integer and non-integer r.
+func TestNegativeBinomialMoments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct{ r, p float64 }{
+		{1, 0.5},
+		{0.5, 0.3},
+		{3, 0.9},
+		{2.5, 0.1},
+		{100, 0.5},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("r=%v,p=%v", tc.r, tc.p), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(tc.r*10 + tc.p*100))
+			mean, variance := sampleMoments(trials, func() float64 {
+				return float64(NegativeBinomial(src, tc.r, tc.p))
+			})
+			q := 1 - tc.p
+			expectedMean := tc.r * q / tc.p
+			expectedVariance := expectedMean / tc.p
+			// The excess kurtosis is 6/r + p²/(r(1-p)), which bounds the standard error of the
+			// variance.
+			kurtosis := 6/tc.r + tc.p*tc.p/(tc.r*q)
+			require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials))
+			require.InDelta(t, expectedVariance, variance,
+				5*expectedVariance*math.Sqrt((2+kurtosis)/trials))
+		})
+	}
+}
+
+// TestNegativeBinomialGeometric checks that NegativeBinomial() with r == 1 gives the geometric distribution,
+// i.e. k comes up with probability (1-p)^k p.
+func TestNegativeBinomialGeometric(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const p = 0.4
+	src := rand.NewSource(1)
+	var counts [5]int
+	for i := 0; i < trials; i++ {
+		if k := NegativeBinomial(src, 1, p); k < len(counts) {
+			counts[k]++
+		}
+	}
+	for k, count := range counts {
+		requireBinomialCount(t, trials, math.Pow(1-p, float64(k))*p, count, "k=%d", k)
+	}
+}
+
+// TestNegativeBinomialCertain checks that NegativeBinomial() returns 0 without using any randomness for
+// p == 1.
+func TestNegativeBinomialCertain(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, 0, NegativeBinomial(&src, 5, 1))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestNegativeBinomialInvalid checks that NegativeBinomial() panics for invalid r or p.
+func TestNegativeBinomialInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, r := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		require.PanicsWithValue(t, "r must be positive and finite in call to NegativeBinomial", func() {
+			NegativeBinomial(&src, r, 0.5)
+		}, "r=%v", r)
+	}
+	for _, p := range []float64{0, -0.1, 1.1, math.NaN(), math.Nextafter(1.0/(1<<20), 0)} {
+		require.PanicsWithValue(t, "p must be in [2⁻²⁰, 1] in call to NegativeBinomial", func() {
+			NegativeBinomial(&src, 1, p)
+		}, "p=%v", p)
+	}
+	require.PanicsWithValue(t, "r(1-p)/p must be at most 2²⁰ in call to NegativeBinomial", func() {
+		NegativeBinomial(&src, math.Nextafter(1<<20, math.Inf(1)), 0.5)
+	})
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestNegativeBinomialLimits checks that NegativeBinomial() works at the edges of its supported range: the
+// smallest p, with the largest mean, for both r == 1 and a tiny r, whose gamma distribution has a heavy
+// right tail relative to its mean.
+func TestNegativeBinomialLimits(t *testing.T) {
+	t.Parallel()
+	const trials = 10000
+	const p = 1.0 / (1 << 20)
+	for _, r := range []float64{1, 1e-6} {
+		src := rand.NewSource(int64(r * 10))
+		mean, _ := sampleMoments(trials, func() float64 {
+			k := NegativeBinomial(src, r, p)
+			require.GreaterOrEqual(t, k, 0, "r=%v", r)
+			return float64(k)
+		})
+		expectedMean := r * (1 - p) / p
+		expectedVariance := expectedMean / p
+		require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials), "r=%v", r)
+	}
+
+	// The largest mean is also allowed with p close to 1.
+	src := rand.NewSource(2)
+	require.GreaterOrEqual(t, NegativeBinomial(src, 1<<40, 1.0/(1+1.0/(1<<20))), 0)
+}
@@ -0,0 +1,21 @@
+package random
+
+import "math"
+
+// NormFloat64 returns a normally-distributed pseudo-random float64 value with mean 0 and standard deviation 1.
+//
+// This uses the Marsaglia polar method (see https://en.wikipedia.org/wiki/Marsaglia_polar_method ), which
+// picks a uniformly-distributed point (u, v) in the unit disk by rejection, and then transforms it into two
+// independent normally-distributed values. Only one of them is returned, since this function is stateless.
+// Unlike some table-based methods, the tails aren't clamped; they're limited only by the smallest non-zero
+// value of u² + v².
+func NormFloat64(src Source) float64 {
+	for {
+		u := 2*Float64(src) - 1
+		v := 2*Float64(src) - 1
+		s := u*u + v*v
+		if s > 0 && s < 1 {
+			return u * math.Sqrt(-2*math.Log(s)/s)
+		}
+	}
+}
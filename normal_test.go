@@ -0,0 +1,65 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sampleMoments returns the sample mean and variance of n draws from draw.
+func sampleMoments(n int, draw func() float64) (mean, variance float64) {
+	var sum, sumSquares float64
+	for i := 0; i < n; i++ {
+		x := draw()
+		sum += x
+		sumSquares += x * x
+	}
+	mean = sum / float64(n)
+	variance = sumSquares/float64(n) - mean*mean
+	return mean, variance
+}
+
+// TestNormFloat64Moments checks that the sample mean and variance of NormFloat64() are about 0 and 1.
+func TestNormFloat64Moments(t *testing.T) {
+	t.Parallel()
+	const trials = 200000
+	src := rand.NewSource(1)
+	mean, variance := sampleMoments(trials, func() float64 {
+		return NormFloat64(src)
+	})
+	// The standard error of the mean is 1/sqrt(trials), and the standard error of the variance is about
+	// sqrt(2/trials).
+	require.InDelta(t, 0, mean, 5/math.Sqrt(trials))
+	require.InDelta(t, 1, variance, 5*math.Sqrt(2.0/trials))
+}
+
+// TestNormFloat64Tails checks that the fraction of NormFloat64() values beyond 2σ (and 3σ) is about right.
+func TestNormFloat64Tails(t *testing.T) {
+	t.Parallel()
+	const trials = 200000
+	src := rand.NewSource(2)
+	beyond2, beyond3 := 0, 0
+	for i := 0; i < trials; i++ {
+		x := math.Abs(NormFloat64(src))
+		if x > 2 {
+			beyond2++
+		}
+		if x > 3 {
+			beyond3++
+		}
+	}
+	requireBinomialCount(t, trials, math.Erfc(2/math.Sqrt2), beyond2)
+	requireBinomialCount(t, trials, math.Erfc(3/math.Sqrt2), beyond3)
+}
+
+// TestNormFloat64Deterministic checks that NormFloat64() returns the same values for the same seed.
+func TestNormFloat64Deterministic(t *testing.T) {
+	t.Parallel()
+	src1 := rand.NewSource(3)
+	src2 := rand.NewSource(3)
+	for i := 0; i < 100; i++ {
+		require.Equal(t, NormFloat64(src1), NormFloat64(src2))
+	}
+}
@@ -0,0 +1,160 @@
+package random
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// A PCG is a Source64 that implements the 32-bit PCG-XSH-RR generator with 64 bits of state (also known as
+// pcg32) from O'Neill's "PCG: A Family of Simple Fast Space-Efficient Statistically Good Algorithms for Random
+// Number Generation", available at https://www.pcg-random.org/ . Unlike the sources returned by
+// rand.NewSource(), its output is completely specified, so it's identical across platforms and Go versions,
+// and matches the reference implementation at https://github.com/imneme/pcg-c-basic .
+//
+// Each call to Uint32() returns one pcg32 output. Uint64() combines two consecutive outputs, with the first one
+// as the high 32 bits, and Int63() returns the top 63 bits of Uint64(). Since Uint32() is available,
+// Uint32n() (and the other functions that need 32-bit values) use a single output per draw.
+//
+// A PCG is not safe for concurrent use by multiple goroutines.
+type PCG struct {
+	state uint64
+	// inc selects the stream, and must be odd.
+	inc uint64
+}
+
+// pcgMultiplier is the multiplier of the underlying linear congruential generator.
+const pcgMultiplier = 6364136223846793005
+
+// NewPCG returns a new PCG with the given initial state and stream selector, like pcg32_srandom_r() in the
+// reference implementation. Only the low 63 bits of inc are used.
+func NewPCG(state, inc uint64) *PCG {
+	p := &PCG{inc: inc<<1 | 1}
+	p.Reseed(state)
+	return p
+}
+
+// Reseed resets p to the given initial state, keeping its stream selector, so that it returns the same values
+// as a new PCG returned by NewPCG(seed, inc) would, where inc is the stream selector p was created with.
+func (p *PCG) Reseed(seed uint64) {
+	p.state = 0
+	p.Uint32()
+	p.state += seed
+	p.Uint32()
+}
+
+// Uint32 returns the next pcg32 output, which is uniformly distributed in the range 0 to 2³²-1 (inclusive).
+func (p *PCG) Uint32() uint32 {
+	old := p.state
+	p.state = old*pcgMultiplier + p.inc
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := int(old >> 59)
+	return bits.RotateLeft32(xorShifted, -rot)
+}
+
+// Uint64 returns two consecutive pcg32 outputs, with the first one as the high 32 bits.
+func (p *PCG) Uint64() uint64 {
+	high := p.Uint32()
+	low := p.Uint32()
+	return uint64(high)<<32 | uint64(low)
+}
+
+// Int63 returns the top 63 bits of p.Uint64().
+func (p *PCG) Int63() int64 {
+	return int64(p.Uint64() >> 1)
+}
+
+// Advance advances p as if delta values had been returned by p.Uint32(), in O(log delta) time, like
+// pcg32_advance_r() in the reference implementation. Since the period is 2⁶⁴, delta can be thought of as
+// being taken modulo 2⁶⁴, so "going back" by k values can be done with Advance(-k).
+func (p *PCG) Advance(delta uint64) {
+	// This uses Brown's algorithm from "Random Number Generation with Arbitrary Strides": an LCG step is
+	// the affine map x ↦ mult*x + plus, so advancing by delta steps is the composition of that map with
+	// itself delta times, which can be computed by repeated squaring.
+	curMult := uint64(pcgMultiplier)
+	curPlus := p.inc
+	accMult := uint64(1)
+	accPlus := uint64(0)
+	for delta > 0 {
+		if delta&1 != 0 {
+			accMult *= curMult
+			accPlus = accPlus*curMult + curPlus
+		}
+		curPlus = (curMult + 1) * curPlus
+		curMult *= curMult
+		delta >>= 1
+	}
+	p.state = accMult*p.state + accPlus
+}
+
+// pcgJumpSize is the number of values that PCG.Jump() skips.
+const pcgJumpSize = 1 << 48
+
+// Jump advances p as if 2⁴⁸ values had been returned by p.Uint32(). Since the period is 2⁶⁴, this splits a
+// single stream into 2¹⁶ non-overlapping substreams of 2⁴⁸ values each.
+func (p *PCG) Jump() {
+	p.Advance(pcgJumpSize)
+}
+
+// Clone returns a new PCG with the same state as p.
+func (p *PCG) Clone() Source {
+	clone := *p
+	return &clone
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the state of p (including its stream selector)
+// compactly, so that it can be saved and restored later, e.g. to resume a long-running simulation. The encoding
+// starts with a version byte, followed by the state and the stream selector as big-endian uint64s.
+func (p *PCG) MarshalBinary() ([]byte, error) {
+	return marshalState(p.state, p.inc), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring the state saved by MarshalBinary(), so that
+// p returns the same values that the encoded PCG would have.
+func (p *PCG) UnmarshalBinary(data []byte) error {
+	var state, inc uint64
+	if err := unmarshalState(data, &state, &inc); err != nil {
+		return err
+	}
+	return p.setState(state, inc)
+}
+
+// GobEncode implements gob.GobEncoder, using the same encoding as MarshalBinary().
+func (p *PCG) GobEncode() ([]byte, error) {
+	return p.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using the same encoding as UnmarshalBinary().
+func (p *PCG) GobDecode(data []byte) error {
+	return p.UnmarshalBinary(data)
+}
+
+// setState sets the state and stream selector of p, which must be odd.
+func (p *PCG) setState(state, inc uint64) error {
+	if inc&1 == 0 {
+		return fmt.Errorf("%w: PCG stream selector %d must be odd", ErrInvalidState, inc)
+	}
+	p.state = state
+	p.inc = inc
+	return nil
+}
+
+// pcgJSON is the JSON encoding of the state of a PCG.
+type pcgJSON struct {
+	State uint64 `json:"state"`
+	Inc   uint64 `json:"inc"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the state and stream selector of p as a JSON object.
+func (p *PCG) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pcgJSON{p.state, p.inc})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring the state saved by MarshalJSON().
+func (p *PCG) UnmarshalJSON(data []byte) error {
+	var j pcgJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	return p.setState(j.State, j.Inc)
+}
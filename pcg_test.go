@@ -0,0 +1,109 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPCGReference checks that PCG matches the output of the pcg32-demo program from the reference
+// implementation at https://github.com/imneme/pcg-c-basic , which seeds with NewPCG(42, 54).
+func TestPCGReference(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(42, 54)
+	var vs []uint32
+	for i := 0; i < 6; i++ {
+		vs = append(vs, p.Uint32())
+	}
+	require.Equal(t, []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}, vs)
+}
+
+// TestPCGUint64 checks that PCG.Uint64() combines two consecutive outputs, and PCG.Int63() takes its top 63
+// bits.
+func TestPCGUint64(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(42, 54)
+	require.Equal(t, uint64(0xa15c02b77b47f409), p.Uint64())
+	require.Equal(t, int64(0xba1d333083d2f293>>1), p.Int63())
+}
+
+// TestPCGUint32n checks that Uint32n() uses a single PCG output per draw.
+func TestPCGUint32n(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(42, 54)
+	expectedSrc := testSource{vs: []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}}
+	for i := 0; i < 6; i++ {
+		require.Equal(t, Uint32n(&expectedSrc, 1000), Uint32n(p, 1000))
+	}
+}
+
+// TestPCGStreams checks that PCGs with the same state but different stream selectors return different
+// values.
+func TestPCGStreams(t *testing.T) {
+	t.Parallel()
+	p1 := NewPCG(42, 54)
+	p2 := NewPCG(42, 55)
+	same := 0
+	for i := 0; i < 100; i++ {
+		if p1.Uint32() == p2.Uint32() {
+			same++
+		}
+	}
+	require.Less(t, same, 3)
+}
+
+// TestPCGAdvance checks that PCG.Advance(delta) is the same as calling PCG.Uint32() delta times, and that
+// advancing by -delta goes back.
+func TestPCGAdvance(t *testing.T) {
+	t.Parallel()
+	for _, delta := range []uint64{0, 1, 2, 3, 100, 1000, 12345} {
+		p := NewPCG(42, 54)
+		expected := NewPCG(42, 54)
+		p.Advance(delta)
+		for i := uint64(0); i < delta; i++ {
+			expected.Uint32()
+		}
+		require.Equal(t, *expected, *p, "delta=%d", delta)
+
+		p.Advance(-delta)
+		require.Equal(t, *NewPCG(42, 54), *p, "delta=%d", delta)
+	}
+}
+
+// TestPCGAdvanceCompose checks that advancing by a and then by b is the same as advancing by a+b, including
+// for large values, and that advancing by the period is a no-op.
+func TestPCGAdvanceCompose(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(1, 2)
+	expected := NewPCG(1, 2)
+	p.Advance(0x123456789abcdef)
+	p.Advance(0xfedcba987654321)
+	expected.Advance(0x123456789abcdef + 0xfedcba987654321)
+	require.Equal(t, *expected, *p)
+
+	p = NewPCG(1, 2)
+	p.Advance(1 << 63)
+	p.Advance(1 << 63)
+	require.Equal(t, *NewPCG(1, 2), *p)
+}
+
+// TestPCGJump checks that PCG.Jump() advances by 2⁴⁸ values.
+func TestPCGJump(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(3, 4)
+	expected := NewPCG(3, 4)
+	p.Jump()
+	expected.Advance(1 << 48)
+	require.Equal(t, *expected, *p)
+}
+
+// TestPCGClone checks that a clone of a PCG returns the same values as the original, and that advancing one
+// doesn't affect the other.
+func TestPCGClone(t *testing.T) {
+	t.Parallel()
+	p := NewPCG(42, 54)
+	clone := p.Clone()
+	v := p.Int63()
+	p.Uint32()
+	require.Equal(t, v, clone.Int63())
+}
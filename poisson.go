@@ -0,0 +1,76 @@
+package random
+
+import "math"
+
+// poissonPTRSThreshold is the value of lambda above which Poisson() switches from Knuth's method to the PTRS
+// method.
+const poissonPTRSThreshold = 30
+
+// Poisson returns a Poisson-distributed pseudo-random int with mean lambda. lambda must be non-negative and
+// finite.
+//
+// For lambda <= 30, this uses Knuth's method of multiplying uniform values until the product drops below
+// exp(-lambda), which takes about lambda+1 calls to Float64(). For larger lambda, this uses Hörmann's
+// transformed rejection method with squeeze (PTRS) from "The transformed rejection method for generating
+// Poisson random variables" (1993), which takes a small constant expected number of calls to Float64().
+//
+// For lambda == 0, this just returns 0 without using any randomness.
+func Poisson(src Source, lambda float64) int {
+	if !(lambda >= 0) || math.IsInf(lambda, 1) {
+		panic("lambda must be non-negative and finite in call to Poisson")
+	}
+
+	if lambda == 0 {
+		return 0
+	}
+
+	if lambda <= poissonPTRSThreshold {
+		return poissonKnuth(src, lambda)
+	}
+
+	return poissonPTRS(src, lambda)
+}
+
+// poissonKnuth returns a Poisson-distributed pseudo-random int with mean lambda using Knuth's method: it counts
+// how many uniform values can be multiplied together before the product drops below exp(-lambda). The expected
+// number of calls to Float64() is lambda+1, so Poisson() only uses this for 0 < lambda <= 30.
+func poissonKnuth(src Source, lambda float64) int {
+	l := math.Exp(-lambda)
+	k := 0
+	p := Float64(src)
+	for p > l {
+		k++
+		p *= Float64(src)
+	}
+	return k
+}
+
+// poissonPTRS returns a Poisson-distributed pseudo-random int with mean lambda using Hörmann's transformed
+// rejection method with squeeze (PTRS). Its constants are only valid for lambda >= 10, and Poisson() uses it
+// for lambda > 30.
+func poissonPTRS(src Source, lambda float64) int {
+	sqrtLambda := math.Sqrt(lambda)
+	logLambda := math.Log(lambda)
+	b := 0.931 + 2.53*sqrtLambda
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := Float64(src) - 0.5
+		v := Float64(src)
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		// Fast acceptance for the center of the distribution.
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		// Fast rejection for the tails.
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lgammaK1, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -lambda+k*logLambda-lgammaK1 {
+			return int(k)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPoissonMoments checks that the sample mean and variance of Poisson() are both about lambda, for values
+// of lambda on both sides of poissonPTRSThreshold.
+func TestPoissonMoments(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	for _, lambda := range []float64{0.5, 3, 10, 30, 31, 100, 1000, 1e6} {
+		lambda := lambda
+		t.Run(fmt.Sprintf("lambda=%v", lambda), func(t *testing.T) {
+			t.Parallel()
+			src := rand.NewSource(int64(lambda * 10))
+			mean, variance := sampleMoments(trials, func() float64 {
+				return float64(Poisson(src, lambda))
+			})
+			// The standard error of the mean is sqrt(lambda/trials), and the standard error of the
+			// variance is about lambda*sqrt(2/trials) (plus a small correction for the excess
+			// kurtosis 1/lambda).
+			require.InDelta(t, lambda, mean, 5*math.Sqrt(lambda/trials))
+			require.InDelta(t, lambda, variance, 5*lambda*math.Sqrt((2+1/lambda)/trials))
+		})
+	}
+}
+
+// TestPoissonProbabilities checks that the fraction of Poisson() values equal to small k is about right, for
+// values of lambda on both sides of poissonPTRSThreshold.
+func TestPoissonProbabilities(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	for _, lambda := range []float64{2, 40} {
+		src := rand.NewSource(int64(lambda))
+		counts := make(map[int]int)
+		for i := 0; i < trials; i++ {
+			counts[Poisson(src, lambda)]++
+		}
+		mode := int(lambda)
+		for k := mode - 2; k <= mode+2; k++ {
+			lgammaK1, _ := math.Lgamma(float64(k + 1))
+			p := math.Exp(-lambda + float64(k)*math.Log(lambda) - lgammaK1)
+			requireBinomialCount(t, trials, p, counts[k], "lambda=%v k=%d", lambda, k)
+		}
+	}
+}
+
+// TestPoissonZero checks that Poisson() returns 0 without using any randomness for lambda == 0.
+func TestPoissonZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, 0, Poisson(&src, 0))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestPoissonInvalid checks that Poisson() panics for negative, infinite, or NaN lambda.
+func TestPoissonInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, lambda := range []float64{-1, math.Inf(1), math.NaN()} {
+		require.PanicsWithValue(t, "lambda must be non-negative and finite in call to Poisson", func() {
+			Poisson(&src, lambda)
+		}, "lambda=%v", lambda)
+	}
+}
@@ -0,0 +1,37 @@
+package random
+
+// A Rand wraps a Source, and has methods that forward to the package-level functions of the same name,
+// similar to rand.Rand. A Rand has no state other than its Source, so it's cheap to copy.
+type Rand struct {
+	src Source
+}
+
+// New returns a new Rand that uses the given Source.
+func New(src Source) *Rand {
+	return &Rand{src: src}
+}
+
+// Uint32n returns Uint32n(r's Source, n).
+func (r *Rand) Uint32n(n uint32) uint32 {
+	return Uint32n(r.src, n)
+}
+
+// Intn returns Intn(r's Source, n).
+func (r *Rand) Intn(n int) int {
+	return Intn(r.src, n)
+}
+
+// Shuffle calls Shuffle(r's Source, n, swap).
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	Shuffle(r.src, n, swap)
+}
+
+// Perm returns Perm(r's Source, n).
+func (r *Rand) Perm(n int) []int {
+	return Perm(r.src, n)
+}
+
+// Float64 returns Float64(r's Source).
+func (r *Rand) Float64() float64 {
+	return Float64(r.src)
+}
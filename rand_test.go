@@ -0,0 +1,53 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandDeterministic checks that a Rand built on rand.NewSource(1) reproduces a known sequence.
+func TestRandDeterministic(t *testing.T) {
+	t.Parallel()
+	r := New(rand.NewSource(1))
+	var vs []uint32
+	for i := 0; i < 5; i++ {
+		vs = append(vs, r.Uint32n(1000))
+	}
+	require.Equal(t, []uint32{302, 470, 832, 718, 212}, vs)
+}
+
+// TestRandMatchesFunctions checks that the methods of Rand return the same values as the corresponding
+// package-level functions.
+func TestRandMatchesFunctions(t *testing.T) {
+	t.Parallel()
+	r := New(rand.NewSource(2))
+	src := rand.NewSource(2)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, Uint32n(src, 1000), r.Uint32n(1000))
+		require.Equal(t, Intn(src, 1000000), r.Intn(1000000))
+		require.Equal(t, Perm(src, 10), r.Perm(10))
+		require.Equal(t, Float64(src), r.Float64())
+
+		s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		Shuffle(src, len(s), func(i, j int) {
+			s[i], s[j] = s[j], s[i]
+		})
+		rs := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		r.Shuffle(len(rs), func(i, j int) {
+			rs[i], rs[j] = rs[j], rs[i]
+		})
+		require.Equal(t, s, rs)
+	}
+}
+
+// TestRandCopy checks that copies of a Rand share the same underlying Source.
+func TestRandCopy(t *testing.T) {
+	t.Parallel()
+	r := New(rand.NewSource(3))
+	rCopy := *r
+	expectedSrc := rand.NewSource(3)
+	require.Equal(t, Uint32n(expectedSrc, 1000), r.Uint32n(1000))
+	require.Equal(t, Uint32n(expectedSrc, 1000), rCopy.Uint32n(1000))
+}
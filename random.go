@@ -1,5 +1,11 @@
 package random
 
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
 // A Source represents a source of uniformly-distributed pseudo-random int64 values in the range 0 to 2⁶³-1 (inclusive).
 //
 // We only need pseudo-random values in the range 0 to 2³²-1 (inclusive), but we also want implementations of rand.Source
@@ -8,13 +14,54 @@ type Source interface {
 	Int63() int64
 }
 
-// randUint32 turns the output of src.Int63() into a uniformly-distributed pseudo-random uint32 value in the range
-// 0 to 2³²-1 (inclusive).
+// A Source64 is a Source that can also generate uniformly-distributed pseudo-random uint64 values in the range
+// 0 to 2⁶⁴-1 (inclusive) directly, like rand.Source64. (In particular, the sources returned by rand.NewSource()
+// implement Source64.)
+//
+// If a Source passed to a function in this package also implements Source64, then Uint64() is used instead of
+// Int63(), which avoids wasting a bit per call, and also avoids needing two calls for a 64-bit value.
+//
+// In particular, Uint32n() and the functions built on it take the top 32 bits of Uint64() for a Source64,
+// instead of the top 32 bits of Int63() as earlier versions of this package did. The distribution is the
+// same, but the values drawn from a seeded Source64, including one returned by rand.NewSource(), differ from
+// those of earlier versions.
+type Source64 interface {
+	Source
+	Uint64() uint64
+}
+
+// A source32 is a Source that can also generate uniformly-distributed pseudo-random uint32 values in the range
+// 0 to 2³²-1 (inclusive) directly. This is used by BufferedSource, so that it doesn't have to waste half of each
+// buffered Uint64() value.
+type source32 interface {
+	Source
+	Uint32() uint32
+}
+
+// randUint32 turns the output of src.Uint32() (if src is a source32), src.Uint64() (if src is a Source64), or
+// src.Int63() into a uniformly-distributed pseudo-random uint32 value in the range 0 to 2³²-1 (inclusive).
 func randUint32(src Source) uint32 {
+	switch src := src.(type) {
+	case source32:
+		return src.Uint32()
+	case Source64:
+		return uint32(src.Uint64() >> 32)
+	}
 	// Take the top 32 bits, copying rand.Uint32() from https://golang.org/src/math/rand/rand.go .
 	return uint32(src.Int63() >> 31)
 }
 
+// randUint64 turns the output of src.Uint64() (if src is a Source64) or two calls to src.Int63() into a
+// uniformly-distributed pseudo-random uint64 value in the range 0 to 2⁶⁴-1 (inclusive).
+func randUint64(src Source) uint64 {
+	if src64, ok := src.(Source64); ok {
+		return src64.Uint64()
+	}
+	// Take the top 32 bits of the first call and the bottom 32 bits of the second call, copying rand.Uint64()
+	// from https://golang.org/src/math/rand/rand.go .
+	return uint64(src.Int63())>>31 | uint64(src.Int63())<<32
+}
+
 /*
 The algorithm used by Uint32n() below is taken from Lemire's "Fast Random Integer Generation in an Interval",
 available at https://arxiv.org/abs/1805.10941 . See also
@@ -124,14 +171,37 @@ In fact, the final implementation of Uint32n() avoids even the single remainder
 see the comments in the function for details!
 */
 
+// ErrZeroN is the error returned by Uint32nErr() when n is zero. Use errors.Is() to check for it.
+var ErrZeroN = errors.New("n must be non-zero")
+
 // Uint32n returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be non-zero.
 //
 // This function is basically the internal function rand.int31n() from https://golang.org/src/math/rand/rand.go ,
-// edited for clarity.
+// edited for clarity. It's a wrapper around Uint32nErr() that panics if n is zero.
 func Uint32n(src Source, n uint32) uint32 {
-	if n == 0 {
+	v, err := Uint32nErr(src, n)
+	if err != nil {
 		panic("n must be non-zero in call to Uint32n")
 	}
+	return v
+}
+
+// Uint32nOneBased returns a uniformly-distributed number in the range 1 to n (inclusive), i.e. Uint32n(src, n)+1,
+// which is handy for one-based values like dice rolls or lottery numbers. n must be non-zero.
+func Uint32nOneBased(src Source, n uint32) uint32 {
+	v, err := Uint32nErr(src, n)
+	if err != nil {
+		panic("n must be non-zero in call to Uint32nOneBased")
+	}
+	return v + 1
+}
+
+// Uint32nErr is like Uint32n(), except that it returns ErrZeroN instead of panicking if n is zero. This is
+// useful when n comes from user input.
+func Uint32nErr(src Source, n uint32) (uint32, error) {
+	if n == 0 {
+		return 0, ErrZeroN
+	}
 
 	// As mentioned above, we have one more trick to avoid doing the remainder operation most of the time.
 	// First we pull out the first iteration of the loop:
@@ -141,7 +211,7 @@ func Uint32n(src Source, n uint32) uint32 {
 	// Then we know that threshold < n, so if low ≥ n, then we already know that low ≥ threshold without having
 	// to explicitly calculate threshold.
 	if low >= n {
-		return uint32(prod >> 32)
+		return uint32(prod >> 32), nil
 	}
 
 	// Here we want to calculate 2³² % n, but 2³² doesn't fit in a 32-bit integer. Adding or subtracting n
@@ -154,7 +224,7 @@ func Uint32n(src Source, n uint32) uint32 {
 	//   2³² % n == -n % n.
 	threshold := -n % n
 	if low >= threshold {
-		return uint32(prod >> 32)
+		return uint32(prod >> 32), nil
 	}
 
 	// Since we've already calculated threshold, we can just fall back to the loop described above (***).
@@ -163,7 +233,160 @@ func Uint32n(src Source, n uint32) uint32 {
 		prod = uint64(v) * uint64(n)
 		low = uint32(prod)
 		if low >= threshold {
-			return uint32(prod >> 32)
+			return uint32(prod >> 32), nil
+		}
+	}
+}
+
+// Uint64n returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be non-zero.
+//
+// This is the same algorithm as Uint32n, with 2³² replaced by 2⁶⁴ everywhere. Since there's no built-in 128-bit
+// integer type, bits.Mul64() is used to compute the high and low 64 bits of v*n. Note that unless src is a
+// Source64, each value of v requires two calls to src.Int63(), since a Source only yields 63 bits per call.
+func Uint64n(src Source, n uint64) uint64 {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint64n")
+	}
+
+	// See the comments in Uint32n for an explanation of the steps below.
+	v := randUint64(src)
+	high, low := bits.Mul64(v, n)
+	if low >= n {
+		return high
+	}
+
+	threshold := -n % n
+	if low >= threshold {
+		return high
+	}
+
+	for {
+		v = randUint64(src)
+		high, low = bits.Mul64(v, n)
+		if low >= threshold {
+			return high
+		}
+	}
+}
+
+// Int32n returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be positive.
+//
+// This is a drop-in replacement for rand.Int31n() that uses Uint32n() instead of the slower algorithm used by
+// rand.Int31n().
+func Int32n(src Source, n int32) int32 {
+	if n <= 0 {
+		panic("n must be positive in call to Int32n")
+	}
+
+	return int32(Uint32n(src, uint32(n)))
+}
+
+// Int64n returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be positive.
+//
+// This is a replacement for rand.Int63n() that uses Uint64n() instead of the slower algorithm used by
+// rand.Int63n(), which does two remainder operations for each call when n isn't a power of two. Note that
+// Int64n() does not return the same values as rand.Int63n() for the same Source: rand.Int63n() uses a single
+// call to src.Int63() per attempt, masking for powers of two and taking the remainder otherwise, whereas
+// Int64n() uses two calls to src.Int63() per attempt (see Uint64n()) and takes the high 64 bits of the product.
+func Int64n(src Source, n int64) int64 {
+	if n <= 0 {
+		panic("n must be positive in call to Int64n")
+	}
+
+	return int64(Uint64n(src, uint64(n)))
+}
+
+// Intn returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be positive.
+//
+// On platforms where int is 32 bits, this uses Uint32n(); otherwise, it uses Uint64n().
+func Intn(src Source, n int) int {
+	return intn(src, n, bits.UintSize)
+}
+
+// intn is Intn(), except that the size of int in bits is passed in as intSize, which must be 32 or 64. This is
+// so that both code paths can be tested on any platform.
+func intn(src Source, n int, intSize int) int {
+	if n <= 0 {
+		panic("n must be positive in call to Intn")
+	}
+
+	if intSize == 32 {
+		// This can only happen if intSize doesn't match the actual size of int, but check anyway to avoid
+		// silently truncating n.
+		if uint64(n) > math.MaxUint32 {
+			panic("n must fit in 32 bits in call to Intn")
+		}
+		return int(Uint32n(src, uint32(n)))
+	}
+
+	return int(Uint64n(src, uint64(n)))
+}
+
+// Uint32nBiased returns a number in the range 0 to n-1 (inclusive), with a small bias. n must be non-zero.
+//
+// This is BiasedUint32n() from the comments for Uint32n(), which does a single multiply and shift, and never
+// rejects, so it always uses exactly one draw from src. The cost is that if n doesn't divide 2³², then some
+// values are returned with probability ⌈2³²/n⌉/2³² and others with probability ⌊2³²/n⌋/2³², so each
+// probability is off from 1/n by less than 1/2³², and the total bias (the sum of those differences) is at most
+// n/2³². Only use this if that bias doesn't matter, e.g. for visual effects.
+func Uint32nBiased(src Source, n uint32) uint32 {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint32nBiased")
+	}
+
+	return uint32((uint64(randUint32(src)) * uint64(n)) >> 32)
+}
+
+// LegacyModuloUint32n returns randUint32(src) % n, with no rejection, i.e. the biased modulo method from the
+// comments for Uint32n(). n must be non-zero.
+//
+// DO NOT USE THIS IN NEW CODE. It exists only to reproduce, bit for bit, the outputs of legacy systems that
+// used the modulo method, e.g. to replay their logs. If n doesn't divide 2³², then the values less than 2³² % n
+// are returned with probability ⌈2³²/n⌉/2³², and the others with probability ⌊2³²/n⌋/2³², which is the same
+// amount of bias as Uint32nBiased(), but concentrated on the small values; and it's slower than Uint32n(),
+// since it always does a remainder operation. Use Uint32n() instead, or Uint32nBiased() if bias is acceptable.
+func LegacyModuloUint32n(src Source, n uint32) uint32 {
+	if n == 0 {
+		panic("n must be non-zero in call to LegacyModuloUint32n")
+	}
+
+	return randUint32(src) % n
+}
+
+// Uint32nBounded returns a uniformly-distributed number in the range 0 to n-1 (inclusive) and true, like
+// Uint32n(), unless it has to reject more than maxRetries values, in which case it gives up and returns the
+// result of the last draw, as Uint32nBiased() would have computed it, and false. n must be non-zero, and
+// maxRetries must be non-negative.
+//
+// This bounds the number of draws from src to maxRetries+1, which gives a hard ceiling on latency for real-time
+// code. Each draw is rejected with probability RejectionProbability(n), which is less than both n/2³² and 1/2,
+// so this gives up with probability less than 2^-(maxRetries+1) for any n, and much less for small n; e.g.,
+// for n = 1000 and maxRetries = 1, it's less than 2⁻³⁸. When it does give up, the returned value has the same
+// small bias as Uint32nBiased().
+func Uint32nBounded(src Source, n uint32, maxRetries int) (uint32, bool) {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint32nBounded")
+	}
+	if maxRetries < 0 {
+		panic("maxRetries must be non-negative in call to Uint32nBounded")
+	}
+
+	// See the comments in Uint32nErr() for an explanation of the steps below.
+	v := randUint32(src)
+	prod := uint64(v) * uint64(n)
+	low := uint32(prod)
+	if low >= n {
+		return uint32(prod >> 32), true
+	}
+
+	threshold := -n % n
+	for i := 0; low < threshold; i++ {
+		if i == maxRetries {
+			return uint32(prod >> 32), false
 		}
+		v = randUint32(src)
+		prod = uint64(v) * uint64(n)
+		low = uint32(prod)
 	}
+	return uint32(prod >> 32), true
 }
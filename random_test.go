@@ -1,7 +1,10 @@
 package random
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/bits"
 	"math/rand"
 	"testing"
 
@@ -69,6 +72,67 @@ func makeTestSource(rejectionCount int, v uint32) testSource {
 	return testSource{vs: append(vs, []uint32{v, 0xffffffff}...)}
 }
 
+// int63Source is a source that returns a series of raw int63 values for testing, for functions that use
+// more than the top 32 bits of each call to Int63().
+type int63Source struct {
+	vs        []int64
+	callCount int
+}
+
+// Int63() returns the next value in src.vs, or panics if there aren't any left.
+func (src *int63Source) Int63() int64 {
+	if src.callCount >= len(src.vs) {
+		panic("ran out of vs to return")
+	}
+
+	i := src.callCount
+	src.callCount++
+	return src.vs[i]
+}
+
+// testUint64Source is a Source64 that returns a series of uint64 values from Uint64() for testing. Int63()
+// panics, since it shouldn't be called on a Source64.
+type testUint64Source struct {
+	vs        []uint64
+	callCount int
+}
+
+// Int63() always panics.
+func (src *testUint64Source) Int63() int64 {
+	panic("Int63 called on a Source64")
+}
+
+// Uint64() returns the next value in src.vs, or panics if there aren't any left.
+func (src *testUint64Source) Uint64() uint64 {
+	if src.callCount >= len(src.vs) {
+		panic("ran out of vs to return")
+	}
+
+	i := src.callCount
+	src.callCount++
+	return src.vs[i]
+}
+
+// makeTestUint64Source is like makeTestSource, except that it returns a testUint64Source, where each uint32
+// value is in the top 32 bits of each uint64 value, and the bottom 32 bits are filled with the given junk.
+func makeTestUint64Source(rejectionCount int, v, junk uint32) testUint64Source {
+	testSrc := makeTestSource(rejectionCount, v)
+	vs := make([]uint64, len(testSrc.vs))
+	for i, v := range testSrc.vs {
+		vs[i] = uint64(v)<<32 | uint64(junk)
+	}
+	return testUint64Source{vs: vs}
+}
+
+// requireBinomialCount checks that count, the number of times an event with probability p happened over the
+// given number of independent trials, is within 5 standard deviations of the expected count. This is used by
+// the statistical tests, which use fixed seeds so that they're deterministic.
+func requireBinomialCount(t *testing.T, trials int, p float64, count int, msgAndArgs ...interface{}) {
+	expected := float64(trials) * p
+	stddev := math.Sqrt(float64(trials) * p * (1 - p))
+	require.InDelta(t, expected, float64(count), 5*stddev, msgAndArgs...)
+}
+
 // testUniformUint loops through all numBits-bit values and checks to make sure that
 // uintn() returns the values 0 to n-1 an equal number of times, filtering out
 // the case where the first value is rejected.
@@ -313,44 +377,670 @@ func TestUint32nCloseToMax(t *testing.T) {
 	}
 }
 
-// Benchmarks
-// ----------
+// uint64n returns a uniformly-distributed number in the range 0 to n-1 (inclusive). n must be non-zero, and
+// must fit in numBits bits. numBits must be at least 1 and less than 64.
+//
+// This is a more general and simplified version of Uint64n for testing, analogous to uintn.
+func uint64n(src Source, n uint64, numBits uint) uint64 {
+	if n == 0 {
+		panic("n must be non-zero in call to Uint64n")
+	}
 
-// randInt63n is a copy of rand.Int63n() that is called by shuffleUint32n() and shuffleRandInt31n().
-// It's actually never executed; we just have this here so that the shuffle functions are as close
-// as possible to rand.Shuffle().
-func randInt63n(src Source, n int64) int64 {
-	if n <= 0 {
-		panic("invalid argument to Int63n")
+	if numBits >= 64 {
+		panic("numBits must be less than 64")
 	}
-	if n&(n-1) == 0 { // n is power of two, can mask
-		return src.Int63() & (n - 1)
+
+	if n >= 1<<numBits {
+		panic("n must fit in numBits bits")
 	}
-	max := int64((1 << 63) - 1 - (1<<63)%uint64(n))
-	v := src.Int63()
-	for v > max {
-		v = src.Int63()
+
+	// Mask used to mask off all but the lower numBits bits of v and low.
+	mask := uint64(1)<<numBits - 1
+
+	threshold := (1 << numBits) % n
+	for {
+		v := randUint64(src) & mask
+		high, low := bits.Mul64(v, n)
+		// Shift the 128-bit value high:low right by numBits.
+		prodHigh := high<<(64-numBits) | low>>numBits
+		prodLow := low & mask
+		if prodLow >= threshold {
+			return prodHigh
+		}
 	}
-	return v % n
 }
 
-// shuffleUint32n is a copy of rand.Shuffle() that uses Uint32n() instead of rand.int31n().
-func shuffleUint32n(src Source, n int, swap func(i, j int)) {
-	if n < 0 {
-		panic("invalid argument to shuffleUint32n")
+// testSource64 is a source that returns a series of uint64 values for testing, each one split across two
+// calls to Int63().
+type testSource64 struct {
+	vs        []uint64
+	callCount int
+}
+
+// Int63() returns the appropriate half of the next value in src.vs shifted appropriately, or panics if there
+// aren't any left.
+func (src *testSource64) Int63() int64 {
+	i := src.callCount / 2
+	if i >= len(src.vs) {
+		panic("ran out of vs to return")
 	}
 
-	i := n - 1
-	for ; i > 1<<31-1-1; i-- {
-		j := int(randInt63n(src, int64(i+1)))
-		swap(i, j)
+	first := src.callCount%2 == 0
+	src.callCount++
+	// randUint64() uses the top 32 bits of the first call and the bottom 32 bits of the second call.
+	if first {
+		return int64(src.vs[i]&0xffffffff) << 31
 	}
-	for ; i > 0; i-- {
-		j := int(Uint32n(src, uint32(i+1)))
-		swap(i, j)
+	return int64(src.vs[i] >> 32)
+}
+
+// makeTestSource64 is like makeTestSource, but returns a testSource64. Note that src.callCount will be twice
+// the number of values consumed.
+func makeTestSource64(rejectionCount int, v uint64) testSource64 {
+	vs := make([]uint64, rejectionCount)
+	return testSource64{vs: append(vs, []uint64{v, 0xffffffffffffffff}...)}
+}
+
+// testUniformUint64 is like testUniformUint, but for uint64n().
+func testUniformUint64(t *testing.T, n uint64, numBits uint) {
+	buckets := make([]uint64, n)
+	for v := uint64(0); v < (1 << numBits); v++ {
+		src := makeTestSource64(0, v)
+		u := uint64n(&src, n, numBits)
+		if src.callCount == 4 {
+			// v was rejected, so continue.
+			continue
+		}
+		require.Equal(t, 2, src.callCount)
+		require.Less(t, u, n)
+		buckets[u]++
+	}
+	expectedCount := (1 << numBits) / n
+	for i := uint64(0); i < n; i++ {
+		require.Equal(t, expectedCount, buckets[i], "i=%d", i)
+	}
+}
+
+// TestUniformUint64 exhaustively tests small values for numBits, and all possible values of n for each
+// value of numBits.
+func TestUniformUint64(t *testing.T) {
+	t.Parallel()
+	for numBits := uint(1); numBits < 10; numBits++ {
+		numBits := numBits // capture range variable.
+		t.Run(fmt.Sprintf("numBits=%d", numBits), func(t *testing.T) {
+			t.Parallel()
+			for n := uint64(1); n < 1<<numBits; n++ {
+				testUniformUint64(t, n, numBits)
+			}
+		})
+	}
+}
+
+// TestUint64nMatchesUint64nBits checks that Uint64n() agrees with uint64n() for numBits up to 40, using values
+// of v spread out over the numBits-bit range, shifted up to fill all 64 bits for Uint64n(). Since the
+// thresholds for the two functions differ, only values of v that are accepted by both are compared.
+func TestUint64nMatchesUint64nBits(t *testing.T) {
+	t.Parallel()
+	for numBits := uint(1); numBits <= 40; numBits++ {
+		ns := []uint64{1, 1<<numBits - 1, 1<<(numBits-1) + 1, 3 * (1 << numBits) / 4}
+		for _, n := range ns {
+			if n == 0 || n >= 1<<numBits {
+				continue
+			}
+			vDelta := uint64(1)<<numBits/1000 + 1
+			for v := uint64(0); v < 1<<numBits; v += vDelta {
+				src := makeTestSource64(0, v)
+				expected := uint64n(&src, n, numBits)
+				if src.callCount != 2 {
+					continue
+				}
+
+				src = makeTestSource64(0, v<<(64-numBits))
+				actual := Uint64n(&src, n)
+				if src.callCount != 2 {
+					continue
+				}
+
+				require.Equal(t, expected, actual, "numBits=%d n=%d v=%d", numBits, n, v)
+			}
+		}
+	}
+}
+
+// computeVStart64 is like computeVStart, but for Uint64n(). Since the result for i == n is 2⁶⁴, the result is
+// returned modulo 2⁶⁴.
+func computeVStart64(i, n uint64) uint64 {
+	if i == n {
+		return 0
+	}
+	// Compute ceil((i*2⁶⁴)/n) == floor((i*2⁶⁴ + (n - 1))/n).
+	quo, _ := bits.Div64(i, n-1, n)
+	return quo
+}
+
+// testV64 checks that the given value of v does indeed make Uint64n(src, n) return i.
+func testV64(t *testing.T, rejectionCount int, i, n, v uint64) {
+	src := makeTestSource64(rejectionCount, v)
+	u := Uint64n(&src, n)
+	require.Equal(t, 2*(rejectionCount+1), src.callCount)
+	require.Equal(t, i, u)
+}
+
+// testVStart64 is like testVStart, but for Uint64n().
+func testVStart64(t *testing.T, rejectionCount int, i, n, vStart uint64) uint64 {
+	src := makeTestSource64(rejectionCount, vStart)
+	u := Uint64n(&src, n)
+	if n&(n-1) != 0 && src.callCount == 2*(rejectionCount+2) {
+		// n is not a power of two and vStart was rejected, so the actual vStart must be one higher.
+		vStart++
+		src = makeTestSource64(rejectionCount, vStart)
+		u = Uint64n(&src, n)
+	}
+	require.Equal(t, 2*(rejectionCount+1), src.callCount)
+	require.Equal(t, i, u)
+	return vStart
+}
+
+// testUint64i is like testUint32i, but for Uint64n(). All arithmetic on v is done modulo 2⁶⁴, so n must be
+// at least 2 for the size of the range to fit in a uint64.
+func testUint64i(t *testing.T, rejectionCount int, i, n, vPoints uint64) {
+	vStart := computeVStart64(i, n)
+	vEnd := computeVStart64(i+1, n)
+
+	// Check that vStart-1 yields (i - 1) (mod n).
+	var iPrev uint64
+	if i == 0 {
+		iPrev = n - 1
+	} else {
+		iPrev = i - 1
+	}
+	testV64(t, rejectionCount, iPrev, n, vStart-1)
+
+	// Check that vStart yields i (mod n).
+	vStart = testVStart64(t, rejectionCount, i, n, vStart)
+
+	// Check that the range is the right size, i.e. floor(2⁶⁴/n).
+	expectedCount := 0xffffffffffffffff / n
+	if n&(n-1) == 0 {
+		expectedCount++
+	}
+	count := vEnd - vStart
+	require.Equal(t, expectedCount, count)
+
+	// Check that points in the middle of the range yield i (mod n).
+	vDelta := (count + vPoints - 1) / vPoints
+	for offset := vDelta; offset < count-1; offset += vDelta {
+		testV64(t, rejectionCount, i, n, vStart+offset)
+	}
+
+	// Check that vEnd-1 yields i (mod n).
+	testV64(t, rejectionCount, i, n, vEnd-1)
+
+	// Check that vEnd yields (i + 1) (mod n).
+	testVStart64(t, rejectionCount, (i+1)%n, n, vEnd)
+}
+
+// testUint64n calls testUint64i for about iPoints values of i evenly spread from 0 up to n-1.
+func testUint64n(t *testing.T, rejectionCount int, n, iPoints, vPoints uint64) {
+	iDelta := n/iPoints + 1
+	for i := uint64(0); i < n-1 && i < n-iDelta; i += iDelta {
+		testUint64i(t, rejectionCount, i, n, vPoints)
+	}
+	testUint64i(t, rejectionCount, n-1, n, vPoints)
+}
+
+// TestUint64n* calls testUint64n for values of n around all powers of two, similar to the TestUint32n*
+// functions above.
+
+func TestUint64nOne(t *testing.T) {
+	t.Parallel()
+	for _, v := range []uint64{0, 1, 0x8000000000000000, 0xffffffffffffffff} {
+		src := makeTestSource64(0, v)
+		require.Equal(t, uint64(0), Uint64n(&src, 1))
+		require.Equal(t, 2, src.callCount)
+	}
+}
+
+func TestUint64nPowersOfTwo(t *testing.T) {
+	t.Parallel()
+	for i := uint(1); i < 64; i++ {
+		n := uint64(1) << i
+		testUint64n(t, 0, n, 64, 10)
+	}
+}
+
+func TestUint64nCloseToPowerOfTwo(t *testing.T) {
+	t.Parallel()
+	for i := uint(2); i < 64; i++ {
+		n := uint64(1) << i
+		for r := 0; r < 2; r++ {
+			testUint64n(t, r, n-1, 64, 10)
+			testUint64n(t, r, n+1, 64, 10)
+		}
+	}
+}
+
+func TestUint64nBetweenPowersOfTwo(t *testing.T) {
+	t.Parallel()
+	for i := uint(2); i < 64; i++ {
+		n := 3 * (uint64(1) << (i - 1))
+		for r := 0; r < 2; r++ {
+			testUint64n(t, r, n, 64, 10)
+		}
+	}
+}
+
+func TestUint64nCloseToMax(t *testing.T) {
+	t.Parallel()
+	for i := uint64(0); i < 50; i++ {
+		n := 0xffffffffffffffff - i
+		for r := 0; r < 2; r++ {
+			testUint64n(t, r, n, 64, 10)
+		}
+	}
+}
+
+// TestUint32nErr checks that Uint32nErr() returns ErrZeroN for n == 0, and the same value as Uint32n() with
+// a nil error otherwise.
+func TestUint32nErr(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	v, err := Uint32nErr(&src, 0)
+	require.True(t, errors.Is(err, ErrZeroN))
+	require.Equal(t, uint32(0), v)
+	require.Equal(t, 0, src.callCount)
+
+	for _, n := range []uint32{1, 3, 1 << 20, 0xffffffff} {
+		src := makeTestSource(0, 0xffffffff)
+		v, err := Uint32nErr(&src, n)
+		require.NoError(t, err)
+		require.Equal(t, n-1, v)
+
+		expectedSrc := makeTestSource(0, 0xffffffff)
+		require.Equal(t, Uint32n(&expectedSrc, n), v)
+		require.Equal(t, expectedSrc.callCount, src.callCount)
+	}
+}
+
+// TestUint32nOneBased checks that Uint32nOneBased() returns Uint32n() + 1, so that its minimum is 1 and its
+// maximum is n.
+func TestUint32nOneBased(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint32{1, 3, 1 << 20, 0xffffffff} {
+		// (v*n)>>32 is 0 for this v, and v*n is at least 2³² - n, so it's never rejected.
+		src := testSource{vs: []uint32{0xffffffff / n}}
+		require.Equal(t, uint32(1), Uint32nOneBased(&src, n), "n=%d", n)
+		src = makeTestSource(0, 0xffffffff)
+		require.Equal(t, n, Uint32nOneBased(&src, n), "n=%d", n)
+
+		src = makeTestSource(2, 0x12345678)
+		expectedSrc := makeTestSource(2, 0x12345678)
+		require.Equal(t, Uint32n(&expectedSrc, n)+1, Uint32nOneBased(&src, n), "n=%d", n)
+		require.Equal(t, expectedSrc.callCount, src.callCount, "n=%d", n)
+	}
+}
+
+// TestUint32nOneBasedUniform checks that the values from Uint32nOneBased() are roughly uniform over [1, n].
+func TestUint32nOneBasedUniform(t *testing.T) {
+	t.Parallel()
+	const n = 6
+	const trials = 100000
+	src := rand.NewSource(1)
+	var counts [n + 1]int
+	for i := 0; i < trials; i++ {
+		counts[Uint32nOneBased(src, n)]++
+	}
+	require.Equal(t, 0, counts[0])
+	for v := 1; v <= n; v++ {
+		requireBinomialCount(t, trials, 1.0/n, counts[v], "v=%d", v)
+	}
+}
+
+// TestUint32nOneBasedZero checks that Uint32nOneBased() panics for n == 0.
+func TestUint32nOneBasedZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Uint32nOneBased", func() {
+		Uint32nOneBased(&src, 0)
+	})
+}
+
+// TestUint32nZero checks that Uint32n() panics for n == 0.
+func TestUint32nZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Uint32n", func() {
+		Uint32n(&src, 0)
+	})
+}
+
+// TestUint32nBiased checks that Uint32nBiased() returns values in range using exactly one draw, including for
+// values of v that Uint32n() would reject.
+func TestUint32nBiased(t *testing.T) {
+	t.Parallel()
+	ns := []uint32{1, 2, 3, 7, 1000, 1 << 20, 0x80000001, 0xffffffff}
+	vs := []uint32{0, 1, 0x7fffffff, 0x80000000, 0xffffffff}
+	for _, n := range ns {
+		for _, v := range vs {
+			src := makeTestSource(0, v)
+			u := Uint32nBiased(&src, n)
+			require.Less(t, u, n, "n=%d v=%d", n, v)
+			require.Equal(t, uint32(uint64(v)*uint64(n)>>32), u, "n=%d v=%d", n, v)
+			require.Equal(t, 1, src.callCount, "n=%d v=%d", n, v)
+		}
+	}
+}
+
+// TestUint32nBiasedCounts checks the bias documented for Uint32nBiased(), using a numBits-bit version of it: for
+// small values of numBits and all n, each value is returned either floor(2^numBits/n) or ceil(2^numBits/n)
+// times over all possible inputs.
+func TestUint32nBiasedCounts(t *testing.T) {
+	t.Parallel()
+	for numBits := uint32(1); numBits < 10; numBits++ {
+		for n := uint32(1); n < 1<<numBits; n++ {
+			buckets := make([]uint32, n)
+			for v := uint32(0); v < 1<<numBits; v++ {
+				buckets[(v*n)>>numBits]++
+			}
+			low := uint32(1<<numBits) / n
+			for i, count := range buckets {
+				require.True(t, count == low || count == low+1, "numBits=%d n=%d i=%d count=%d", numBits, n, i, count)
+			}
+		}
+	}
+}
+
+// TestLegacyModuloUint32n checks that LegacyModuloUint32n() returns v % n using exactly one draw, including for
+// values of v that Uint32n() would reject.
+func TestLegacyModuloUint32n(t *testing.T) {
+	t.Parallel()
+	ns := []uint32{1, 2, 3, 7, 1000, 1 << 20, 0x80000001, 0xffffffff}
+	vs := []uint32{0, 1, 999, 1000, 0x7fffffff, 0x80000000, 0xfffffffe, 0xffffffff}
+	for _, n := range ns {
+		for _, v := range vs {
+			src := makeTestSource(0, v)
+			require.Equal(t, v%n, LegacyModuloUint32n(&src, n), "n=%d v=%d", n, v)
+			require.Equal(t, 1, src.callCount, "n=%d v=%d", n, v)
+		}
+	}
+
+	// Check a few values by hand, since the above just repeats the implementation.
+	src := testSource{vs: []uint32{0xffffffff, 0x80000000, 12345}}
+	require.Equal(t, uint32(0), LegacyModuloUint32n(&src, 3))
+	require.Equal(t, uint32(2147483648), LegacyModuloUint32n(&src, 0xffffffff))
+	require.Equal(t, uint32(345), LegacyModuloUint32n(&src, 1000))
+}
+
+// TestLegacyModuloUint32nZero checks that LegacyModuloUint32n() panics for n == 0.
+func TestLegacyModuloUint32nZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to LegacyModuloUint32n", func() {
+		LegacyModuloUint32n(&src, 0)
+	})
+}
+
+// TestUint32nBounded checks that Uint32nBounded() returns the same value as Uint32n() when there are at most
+// maxRetries rejections, and gives up after maxRetries+1 draws otherwise.
+func TestUint32nBounded(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint32{3, 1000, 0x80000001} {
+		for r := 0; r < 3; r++ {
+			expectedSrc := makeTestSource(r, 0x12345678)
+			expected := Uint32n(&expectedSrc, n)
+			// 0x12345678 itself may be rejected, depending on n.
+			rejections := expectedSrc.callCount - 1
+			for maxRetries := 0; maxRetries < 4; maxRetries++ {
+				src := makeTestSource(r, 0x12345678)
+				v, ok := Uint32nBounded(&src, n, maxRetries)
+				if rejections <= maxRetries {
+					require.True(t, ok, "n=%d r=%d maxRetries=%d", n, r, maxRetries)
+					require.Equal(t, expected, v, "n=%d r=%d maxRetries=%d", n, r, maxRetries)
+					require.Equal(t, expectedSrc.callCount, src.callCount, "n=%d r=%d maxRetries=%d", n, r, maxRetries)
+				} else {
+					require.False(t, ok, "n=%d r=%d maxRetries=%d", n, r, maxRetries)
+					require.Equal(t, maxRetries+1, src.callCount, "n=%d r=%d maxRetries=%d", n, r, maxRetries)
+				}
+			}
+		}
+	}
+}
+
+// TestUint32nBoundedTimeout checks that Uint32nBounded() gives up after maxRetries rejections, and returns the
+// biased value for the last draw and false.
+func TestUint32nBoundedTimeout(t *testing.T) {
+	t.Parallel()
+	// As in makeTestSource(), 0 is always rejected if n isn't a power of two.
+	const v = 0
+	for _, n := range []uint32{3, 1000, 0x80000001} {
+		for maxRetries := 0; maxRetries < 5; maxRetries++ {
+			src := testSource{vs: make([]uint32, maxRetries+1)}
+			u, ok := Uint32nBounded(&src, n, maxRetries)
+			require.False(t, ok, "n=%d maxRetries=%d", n, maxRetries)
+			expectedSrc := makeTestSource(0, v)
+			require.Equal(t, Uint32nBiased(&expectedSrc, n), u, "n=%d maxRetries=%d", n, maxRetries)
+			require.Equal(t, maxRetries+1, src.callCount, "n=%d maxRetries=%d", n, maxRetries)
+		}
 	}
 }
 
+// TestUint32nBoundedInvalid checks that Uint32nBounded() panics for n == 0 or negative maxRetries.
+func TestUint32nBoundedInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-zero in call to Uint32nBounded", func() {
+		Uint32nBounded(&src, 0, 1)
+	})
+	require.PanicsWithValue(t, "maxRetries must be non-negative in call to Uint32nBounded", func() {
+		Uint32nBounded(&src, 3, -1)
+	})
+}
+
+// TestInt32n checks Int32n() against a table of expected results for various values of n and v.
+func TestInt32n(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n        int32
+		v        uint32
+		expected int32
+	}{
+		{1, 0, 0},
+		{1, 0x80000000, 0},
+		{1, 0xffffffff, 0},
+		{2, 0, 0},
+		{2, 0x7fffffff, 0},
+		{2, 0x80000000, 1},
+		{2, 0xffffffff, 1},
+		{1 << 20, 0, 0},
+		{1 << 20, 0x00001000, 1},
+		{1 << 20, 0xffffffff, 1<<20 - 1},
+		{math.MaxInt32, 0x00000003, 1},
+		{math.MaxInt32, 0x80000001, 0x3fffffff},
+		{math.MaxInt32, 0xffffffff, math.MaxInt32 - 1},
+		{math.MaxInt32 - 1, 0xffffffff, math.MaxInt32 - 2},
+	}
+	for _, test := range tests {
+		src := makeTestSource(0, test.v)
+		require.Equal(t, test.expected, Int32n(&src, test.n), "n=%d v=%d", test.n, test.v)
+		require.Equal(t, 1, src.callCount, "n=%d v=%d", test.n, test.v)
+	}
+}
+
+// TestInt32nNonPositive checks that Int32n() panics with the right message for non-positive n.
+func TestInt32nNonPositive(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int32{0, -1, math.MinInt32} {
+		src := makeTestSource(0, 0)
+		require.PanicsWithValue(t, "n must be positive in call to Int32n", func() {
+			Int32n(&src, n)
+		}, "n=%d", n)
+	}
+}
+
+// TestInt64n checks Int64n() against a table of expected results for various values of n and v.
+func TestInt64n(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n        int64
+		v        uint64
+		expected int64
+	}{
+		{1, 0, 0},
+		{1, 0xffffffffffffffff, 0},
+		{2, 0x7fffffffffffffff, 0},
+		{2, 0x8000000000000000, 1},
+		{1 << 62, 0, 0},
+		{1 << 62, 0x0000000000000004, 1},
+		{1 << 62, 0xffffffffffffffff, 1<<62 - 1},
+		{math.MaxInt64, 0x0000000000000003, 1},
+		{math.MaxInt64, 0x8000000000000001, 0x3fffffffffffffff},
+		{math.MaxInt64, 0xffffffffffffffff, math.MaxInt64 - 1},
+	}
+	for _, test := range tests {
+		src := makeTestSource64(0, test.v)
+		require.Equal(t, test.expected, Int64n(&src, test.n), "n=%d v=%d", test.n, test.v)
+		require.Equal(t, 2, src.callCount, "n=%d v=%d", test.n, test.v)
+	}
+}
+
+// TestInt64nNonPositive checks that Int64n() panics with the right message for non-positive n.
+func TestInt64nNonPositive(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int64{0, -1, math.MinInt64} {
+		src := makeTestSource64(0, 0)
+		require.PanicsWithValue(t, "n must be positive in call to Int64n", func() {
+			Int64n(&src, n)
+		}, "n=%d", n)
+	}
+}
+
+// TestIntn32 checks that intn() with intSize=32 returns the same values as Uint32n().
+func TestIntn32(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{1, 2, 3, 1 << 20, math.MaxInt32} {
+		for _, v := range []uint32{0, 1, 0x80000000, 0xffffffff} {
+			src := makeTestSource(1, v)
+			expected := Uint32n(&src, uint32(n))
+			expectedCallCount := src.callCount
+
+			src = makeTestSource(1, v)
+			require.Equal(t, int(expected), intn(&src, n, 32), "n=%d v=%d", n, v)
+			require.Equal(t, expectedCallCount, src.callCount, "n=%d v=%d", n, v)
+		}
+	}
+}
+
+// TestIntn32TooLarge checks that intn() with intSize=32 panics instead of truncating an n that doesn't fit in
+// 32 bits. This can only be tested on platforms where int is 64 bits.
+func TestIntn32TooLarge(t *testing.T) {
+	t.Parallel()
+	if bits.UintSize < 64 {
+		t.Skip("int is too small to hold n")
+	}
+	maxUint32 := uint64(math.MaxUint32)
+	src := makeTestSource(0, 0)
+	require.PanicsWithValue(t, "n must fit in 32 bits in call to Intn", func() {
+		intn(&src, int(maxUint32+1), 32)
+	})
+}
+
+// TestIntn64 checks that intn() with intSize=64 returns the same values as Uint64n().
+func TestIntn64(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{1, 2, 3, 1 << 20, math.MaxInt32} {
+		for _, v := range []uint64{0, 1, 0x8000000000000000, 0xffffffffffffffff} {
+			src := makeTestSource64(1, v)
+			expected := Uint64n(&src, uint64(n))
+			expectedCallCount := src.callCount
+
+			src = makeTestSource64(1, v)
+			require.Equal(t, int(expected), intn(&src, n, 64), "n=%d v=%d", n, v)
+			require.Equal(t, expectedCallCount, src.callCount, "n=%d v=%d", n, v)
+		}
+	}
+}
+
+// TestIntn checks that Intn() uses the code path for the platform's size of int.
+func TestIntn(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	expectedSrc := rand.NewSource(1)
+	for i := 0; i < 100; i++ {
+		n := math.MaxInt32 - i
+		require.Equal(t, intn(expectedSrc, n, bits.UintSize), Intn(src, n))
+	}
+}
+
+// TestIntnNonPositive checks that Intn() panics with the right message for non-positive n.
+func TestIntnNonPositive(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{0, -1, math.MinInt32} {
+		src := makeTestSource(0, 0)
+		require.PanicsWithValue(t, "n must be positive in call to Intn", func() {
+			Intn(&src, n)
+		}, "n=%d", n)
+	}
+}
+
+// TestSource64Uint32n checks that Uint32n() returns the same values and uses the same number of draws for a
+// Source64 as for a Source with the same top 32 bits, and that it uses Uint64() exactly once per draw.
+func TestSource64Uint32n(t *testing.T) {
+	t.Parallel()
+	ns := []uint32{1, 2, 3, 7, 1 << 20, 3 << 20, 0x7fffffff, 0x80000000, 0x80000001, 0xffffffff}
+	vs := []uint32{0, 1, 0x7fffffff, 0x80000000, 0xfffffffe, 0xffffffff}
+	for _, n := range ns {
+		for _, v := range vs {
+			for r := 0; r < 2; r++ {
+				src := makeTestSource(r, v)
+				expected := Uint32n(&src, n)
+
+				src64 := makeTestUint64Source(r, v, 0xdeadbeef)
+				require.Equal(t, expected, Uint32n(&src64, n), "n=%d v=%d r=%d", n, v, r)
+				require.Equal(t, src.callCount, src64.callCount, "n=%d v=%d r=%d", n, v, r)
+			}
+		}
+	}
+}
+
+// TestSource64PowerOfTwo checks that Uint32n() and Uint64n() make exactly one call to Uint64() for a power
+// of two n.
+func TestSource64PowerOfTwo(t *testing.T) {
+	t.Parallel()
+	for i := uint32(0); i < 32; i++ {
+		src := testUint64Source{vs: []uint64{0x0123456789abcdef}}
+		Uint32n(&src, 1<<i)
+		require.Equal(t, 1, src.callCount)
+	}
+	for i := uint64(0); i < 64; i++ {
+		src := testUint64Source{vs: []uint64{0x0123456789abcdef}}
+		Uint64n(&src, 1<<i)
+		require.Equal(t, 1, src.callCount)
+	}
+}
+
+// TestSource64Uint64n checks that Uint64n() returns the same values and uses the same number of draws for a
+// Source64 as for a Source that splits its values across two calls to Int63().
+func TestSource64Uint64n(t *testing.T) {
+	t.Parallel()
+	ns := []uint64{1, 2, 3, 7, 1 << 40, 3 << 40, 0x7fffffffffffffff, 0x8000000000000001, 0xffffffffffffffff}
+	vs := []uint64{0, 1, 0x7fffffffffffffff, 0x8000000000000000, 0xffffffffffffffff}
+	for _, n := range ns {
+		for _, v := range vs {
+			for r := 0; r < 2; r++ {
+				src := makeTestSource64(r, v)
+				expected := Uint64n(&src, n)
+
+				src64 := testUint64Source{vs: src.vs}
+				require.Equal(t, expected, Uint64n(&src64, n), "n=%d v=%d r=%d", n, v, r)
+				require.Equal(t, src.callCount, 2*src64.callCount, "n=%d v=%d r=%d", n, v, r)
+			}
+		}
+	}
+}
+
+// Benchmarks
+// ----------
+
 // randInt31 turns the output of src.Int63() into a uniformly-distributed pseudo-random int32 value in the range
 // 0 to 2³¹-1 (inclusive).
 func randInt31(src Source) int32 {
@@ -360,7 +1050,7 @@ func randInt31(src Source) int32 {
 
 // randInt31n is a copy of rand.Int31n() that is called by shuffleRandInt31n. This is because having
 // shuffleRandInt31n call rand.Int31n() slows it down a bit (probably because it's not inlined),
-// and we want to show that the fastest that shuffleRandInt31n can be is still slower than shuffleUint32n.
+// and we want to show that the fastest that shuffleRandInt31n can be is still slower than Shuffle.
 func randInt31n(src Source, n int32) int32 {
 	if n <= 0 {
 		panic("invalid argument to Int31n")
@@ -376,6 +1066,23 @@ func randInt31n(src Source, n int32) int32 {
 	return v % n
 }
 
+// randInt63n is a copy of rand.Int63n(), which is used by shuffleRandInt31n() and shuffleRandInt63n() for values
+// of n that don't fit in 31 bits.
+func randInt63n(src Source, n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int63n")
+	}
+	if n&(n-1) == 0 { // n is power of two, can mask
+		return src.Int63() & (n - 1)
+	}
+	max := int64((1 << 63) - 1 - (1<<63)%uint64(n))
+	v := src.Int63()
+	for v > max {
+		v = src.Int63()
+	}
+	return v % n
+}
+
 // shuffleRandInt31n is a copy of rand.Shuffle() that uses randInt31n() instead of rand.int31n().
 func shuffleRandInt31n(src Source, n int, swap func(i, j int)) {
 	if n < 0 {
@@ -393,11 +1100,31 @@ func shuffleRandInt31n(src Source, n int, swap func(i, j int)) {
 	}
 }
 
-// The BenchmarkLargeShuffle* (Small) functions benchmark a shuffle using Uint32n or randInt31n against
-// rand.Shuffle(), with a large (small) n and a no-op swap function.
+// shuffleRandInt63n is a copy of Shuffle() from before it used Uint64n(), i.e. with randInt63n() for values
+// of n that don't fit in 31 bits.
+func shuffleRandInt63n(src Source, n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("invalid argument to shuffleRandInt63n")
+	}
+
+	i := n - 1
+	for ; i > 1<<31-1-1; i-- {
+		j := int(randInt63n(src, int64(i+1)))
+		swap(i, j)
+	}
+	for ; i > 0; i-- {
+		j := int(Uint32n(src, uint32(i+1)))
+		swap(i, j)
+	}
+}
+
+// The BenchmarkLargeShuffle* (Small) functions benchmark Shuffle() (which uses Uint32n) or a shuffle using
+// randInt31n against rand.Shuffle(), with a large (small) n and a no-op swap function.
 //
-// In my runs, shuffleUint32n() very slightly beats out rand.Shuffle(), probably because of better inlining,
-// and both beat out shuffleRandInt31n().
+// In my runs, Shuffle() very slightly beat out rand.Shuffle(), probably because of better inlining,
+// and both beat out shuffleRandInt31n(). However, since the sources returned by rand.NewSource() implement
+// Source64, Shuffle() now pays for the Source64 check in randUint32(), which also keeps it from being
+// inlined, so it's now somewhat slower than rand.Shuffle() with those sources.
 
 const largeN = 0x0fffffff
 const smallN = 0x0000ffff
@@ -411,7 +1138,7 @@ func BenchmarkLargeShuffleUint32n(b *testing.B) {
 		largeUint32nResult += i + j
 	}
 	for n := 0; n < b.N; n++ {
-		shuffleUint32n(src, largeN, swap)
+		Shuffle(src, largeN, swap)
 	}
 }
 
@@ -447,7 +1174,7 @@ func BenchmarkSmallShuffleUint32n(b *testing.B) {
 		smallUint32nResult += i + j
 	}
 	for n := 0; n < b.N; n++ {
-		shuffleUint32n(src, smallN, swap)
+		Shuffle(src, smallN, swap)
 	}
 }
 
@@ -474,3 +1201,66 @@ func BenchmarkSmallRandShuffle(b *testing.B) {
 		r.Shuffle(smallN, swap)
 	}
 }
+
+// The BenchmarkSmallN* functions benchmark Uint32nBiased() against Uint32n() for a small n.
+
+const smallNBound = 6
+
+var smallNBiasedResult uint32
+
+func BenchmarkSmallNUint32nBiased(b *testing.B) {
+	src := rand.NewSource(14)
+	for n := 0; n < b.N; n++ {
+		smallNBiasedResult += Uint32nBiased(src, smallNBound)
+	}
+}
+
+var smallNUint32nResult uint32
+
+func BenchmarkSmallNUint32n(b *testing.B) {
+	src := rand.NewSource(14)
+	for n := 0; n < b.N; n++ {
+		smallNUint32nResult += Uint32n(src, smallNBound)
+	}
+}
+
+// The BenchmarkHugeShuffle* functions benchmark Shuffle() (which uses Uint64n for values of n that don't fit
+// in 31 bits) against shuffleRandInt63n() (which uses randInt63n for those values) with a virtual array of 3
+// billion elements, i.e. a no-op swap function. Each iteration takes a while, so use e.g. -benchtime=1x.
+//
+// Only the first 850 million or so steps need 64-bit values, and the rest use Uint32n() either way, so in my
+// runs the Uint64n() version was only a few percent faster overall (about 36.2s vs. 37.5s per shuffle), even
+// though randInt63n() does a remainder operation for every value.
+
+// hugeN is a variable instead of a constant so that this file still compiles where int is 32 bits.
+var hugeN int64 = 3000000000
+
+var hugeUint64nResult int
+
+func BenchmarkHugeShuffleUint64n(b *testing.B) {
+	if bits.UintSize < 64 {
+		b.Skip("int is too small")
+	}
+	src := rand.NewSource(14)
+	swap := func(i, j int) {
+		hugeUint64nResult += i + j
+	}
+	for n := 0; n < b.N; n++ {
+		Shuffle(src, int(hugeN), swap)
+	}
+}
+
+var hugeRandInt63nResult int
+
+func BenchmarkHugeShuffleRandInt63n(b *testing.B) {
+	if bits.UintSize < 64 {
+		b.Skip("int is too small")
+	}
+	src := rand.NewSource(14)
+	swap := func(i, j int) {
+		hugeRandInt63nResult += i + j
+	}
+	for n := 0; n < b.N; n++ {
+		shuffleRandInt63n(src, int(hugeN), swap)
+	}
+}
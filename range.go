@@ -0,0 +1,66 @@
+package random
+
+import "math"
+
+// Uint32Range returns a uniformly-distributed number in the range lo to hi (inclusive). lo must be at most hi.
+func Uint32Range(src Source, lo, hi uint32) uint32 {
+	if lo > hi {
+		panic("lo must be at most hi in call to Uint32Range")
+	}
+
+	// The size of the range is hi-lo+1, which is 2³² (and so wraps around to 0) only if lo == 0 and
+	// hi == 2³²-1, in which case any uint32 will do.
+	span := hi - lo + 1
+	if span == 0 {
+		return randUint32(src)
+	}
+	return lo + Uint32n(src, span)
+}
+
+// Int32Range returns a uniformly-distributed number in the range lo to hi (inclusive). lo must be at most hi.
+func Int32Range(src Source, lo, hi int32) int32 {
+	if lo > hi {
+		panic("lo must be at most hi in call to Int32Range")
+	}
+
+	// hi-lo can overflow an int32 (e.g., if lo is very negative and hi is very positive), but it always fits
+	// in a uint32, and two's complement arithmetic means that it can be computed by converting first. As with
+	// Uint32Range(), the size of the range wraps around to 0 only for the full-width range.
+	span := uint32(hi) - uint32(lo) + 1
+	if span == 0 {
+		return int32(randUint32(src))
+	}
+	return int32(uint32(lo) + Uint32n(src, span))
+}
+
+// Float64Range returns a uniformly-distributed pseudo-random float64 value in the range lo to hi (exclusive).
+// lo and hi must be finite, and lo must be less than hi.
+//
+// This computes lo + (hi-lo)*Float64(src), with two adjustments. First, if hi-lo overflows to +Inf (which
+// can happen if lo and hi are both large in magnitude and of opposite sign), it's computed as
+// 2*(lo/2 + (hi/2-lo/2)*Float64(src)) instead, which doesn't overflow. Second, the result can round up to
+// exactly hi if the range is small relative to hi, so in that case the largest float64 less than hi is
+// returned instead.
+func Float64Range(src Source, lo, hi float64) float64 {
+	if math.IsNaN(lo) || math.IsNaN(hi) {
+		panic("lo and hi must not be NaN in call to Float64Range")
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		panic("lo and hi must be finite in call to Float64Range")
+	}
+	if lo >= hi {
+		panic("lo must be less than hi in call to Float64Range")
+	}
+
+	u := Float64(src)
+	var x float64
+	if span := hi - lo; !math.IsInf(span, 1) {
+		x = lo + span*u
+	} else {
+		x = 2 * (lo/2 + (hi/2-lo/2)*u)
+	}
+	if x >= hi {
+		return math.Nextafter(hi, lo)
+	}
+	return x
+}
@@ -0,0 +1,219 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUint32RangeFullWidth checks that Uint32Range() returns the raw uint32 value for the full-width range.
+func TestUint32RangeFullWidth(t *testing.T) {
+	t.Parallel()
+	for _, v := range []uint32{0, 1, 0x80000000, 0xffffffff} {
+		src := makeTestSource(0, v)
+		require.Equal(t, v, Uint32Range(&src, 0, math.MaxUint32))
+		require.Equal(t, 1, src.callCount)
+	}
+}
+
+// TestUint32RangeSingle checks that Uint32Range() always returns lo if lo == hi.
+func TestUint32RangeSingle(t *testing.T) {
+	t.Parallel()
+	for _, lo := range []uint32{0, 1, 12345, math.MaxUint32} {
+		for _, v := range []uint32{0, 0xffffffff} {
+			src := makeTestSource(0, v)
+			require.Equal(t, lo, Uint32Range(&src, lo, lo))
+		}
+	}
+}
+
+// TestUint32RangeBoundaries checks that Uint32Range() can return both lo and hi.
+func TestUint32RangeBoundaries(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ lo, hi uint32 }{{0, 9}, {5, 14}, {1, math.MaxUint32}, {0, math.MaxUint32 - 1}}
+	for _, test := range tests {
+		// 0 would be rejected, so use 1 instead.
+		src := makeTestSource(0, 1)
+		require.Equal(t, test.lo, Uint32Range(&src, test.lo, test.hi))
+		require.Equal(t, 1, src.callCount)
+		src = makeTestSource(0, 0xffffffff)
+		require.Equal(t, test.hi, Uint32Range(&src, test.lo, test.hi))
+	}
+}
+
+// TestUint32RangeUniform checks that Uint32Range() returns roughly uniform values for a mid-size range.
+func TestUint32RangeUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const lo = 1000
+	const hi = 1019
+	src := rand.NewSource(1)
+	var counts [hi - lo + 1]int
+	for i := 0; i < trials; i++ {
+		u := Uint32Range(src, lo, hi)
+		require.True(t, u >= lo && u <= hi, "u=%d", u)
+		counts[u-lo]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1.0/(hi-lo+1), count, "i=%d", i)
+	}
+}
+
+// TestUint32RangeInvalid checks that Uint32Range() panics if lo > hi.
+func TestUint32RangeInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "lo must be at most hi in call to Uint32Range", func() {
+		Uint32Range(&src, 2, 1)
+	})
+}
+
+// TestInt32RangeFullWidth checks that Int32Range() covers the full int32 range for
+// [math.MinInt32, math.MaxInt32].
+func TestInt32RangeFullWidth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		v        uint32
+		expected int32
+	}{
+		{0, 0},
+		{0x7fffffff, math.MaxInt32},
+		{0x80000000, math.MinInt32},
+		{0xffffffff, -1},
+	}
+	for _, test := range tests {
+		src := makeTestSource(0, test.v)
+		require.Equal(t, test.expected, Int32Range(&src, math.MinInt32, math.MaxInt32))
+		require.Equal(t, 1, src.callCount)
+	}
+}
+
+// TestInt32RangeBoundaries checks that Int32Range() can return both lo and hi, including for ranges whose
+// size doesn't fit in an int32.
+func TestInt32RangeBoundaries(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ lo, hi int32 }{
+		{-10, -1},
+		{-5, 4},
+		{-3, 10},
+		{5, 5},
+		{math.MinInt32, math.MaxInt32 - 1},
+		{math.MinInt32 + 1, math.MaxInt32},
+		{math.MinInt32, 0},
+		{-1, math.MaxInt32},
+	}
+	for _, test := range tests {
+		// 0 would be rejected for most ranges, so use 1 instead.
+		src := makeTestSource(0, 1)
+		require.Equal(t, test.lo, Int32Range(&src, test.lo, test.hi), "lo=%d hi=%d", test.lo, test.hi)
+		src = makeTestSource(0, 0xffffffff)
+		require.Equal(t, test.hi, Int32Range(&src, test.lo, test.hi), "lo=%d hi=%d", test.lo, test.hi)
+	}
+}
+
+// TestInt32RangeUniform checks that Int32Range() returns roughly uniform values for a few asymmetric ranges.
+func TestInt32RangeUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	tests := []struct{ lo, hi int32 }{{-3, 10}, {-20, -7}, {math.MinInt32, math.MinInt32 + 4}}
+	for _, test := range tests {
+		src := rand.NewSource(int64(test.lo))
+		counts := make([]int, test.hi-test.lo+1)
+		for i := 0; i < trials; i++ {
+			v := Int32Range(src, test.lo, test.hi)
+			require.True(t, v >= test.lo && v <= test.hi, "v=%d", v)
+			counts[v-test.lo]++
+		}
+		for i, count := range counts {
+			requireBinomialCount(t, trials, 1/float64(len(counts)), count, "lo=%d hi=%d i=%d", test.lo, test.hi, i)
+		}
+	}
+}
+
+// TestInt32RangeInvalid checks that Int32Range() panics if lo > hi.
+func TestInt32RangeInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "lo must be at most hi in call to Int32Range", func() {
+		Int32Range(&src, 1, -1)
+	})
+}
+
+// TestFloat64RangeUniform checks that Float64Range() returns roughly uniform values in a normal range.
+func TestFloat64RangeUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const lo = -1.5
+	const hi = 3.5
+	const buckets = 20
+	src := rand.NewSource(1)
+	var counts [buckets]int
+	for i := 0; i < trials; i++ {
+		x := Float64Range(src, lo, hi)
+		require.True(t, x >= lo && x < hi, "x=%v", x)
+		counts[int((x-lo)/(hi-lo)*buckets)]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1.0/buckets, count, "i=%d", i)
+	}
+}
+
+// TestFloat64RangeNearOverflow checks that Float64Range() returns finite values in range, which are
+// negative about half the time, when hi-lo overflows.
+func TestFloat64RangeNearOverflow(t *testing.T) {
+	t.Parallel()
+	const trials = 10000
+	lo := -math.MaxFloat64
+	hi := math.MaxFloat64
+	src := rand.NewSource(2)
+	negativeCount := 0
+	for i := 0; i < trials; i++ {
+		x := Float64Range(src, lo, hi)
+		require.False(t, math.IsInf(x, 0), "x=%v", x)
+		require.True(t, x >= lo && x < hi, "x=%v", x)
+		if x < 0 {
+			negativeCount++
+		}
+	}
+	requireBinomialCount(t, trials, 0.5, negativeCount)
+
+	// The endpoints should be reachable (or nearly so).
+	minSrc := makeTestSource(0, 0)
+	require.Equal(t, lo, Float64Range(&minSrc, lo, hi))
+	maxSrc := makeTestSource(0, 0xffffffff)
+	x := Float64Range(&maxSrc, lo, hi)
+	require.True(t, x < hi && x > hi*(1-1e-9), "x=%v", x)
+}
+
+// TestFloat64RangeRoundUp checks that Float64Range() doesn't return hi, even if lo + (hi-lo)*Float64(src)
+// rounds up to it.
+func TestFloat64RangeRoundUp(t *testing.T) {
+	t.Parallel()
+	lo := 1.0
+	hi := math.Nextafter(1, 2)
+	src := makeTestSource(0, 0xffffffff)
+	require.Equal(t, lo, Float64Range(&src, lo, hi))
+}
+
+// TestFloat64RangeInvalid checks that Float64Range() panics for invalid ranges.
+func TestFloat64RangeInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, bounds := range [][2]float64{{math.NaN(), 1}, {0, math.NaN()}} {
+		require.PanicsWithValue(t, "lo and hi must not be NaN in call to Float64Range", func() {
+			Float64Range(&src, bounds[0], bounds[1])
+		}, "bounds=%v", bounds)
+	}
+	for _, bounds := range [][2]float64{{math.Inf(-1), 0}, {0, math.Inf(1)}} {
+		require.PanicsWithValue(t, "lo and hi must be finite in call to Float64Range", func() {
+			Float64Range(&src, bounds[0], bounds[1])
+		}, "bounds=%v", bounds)
+	}
+	for _, bounds := range [][2]float64{{1, 1}, {2, 1}} {
+		require.PanicsWithValue(t, "lo must be less than hi in call to Float64Range", func() {
+			Float64Range(&src, bounds[0], bounds[1])
+		}, "bounds=%v", bounds)
+	}
+}
@@ -0,0 +1,10 @@
+package random
+
+// A Reseedable is a Source whose state can be reset in place from a uint64 seed, which avoids allocating a new
+// Source, e.g. when restarting a simulation. SplitMix64, PCG, and Xoshiro256 implement Reseedable.
+type Reseedable interface {
+	Source
+	// Reseed resets the Reseedable so that it returns the same values as a newly-constructed one with the
+	// given seed would.
+	Reseed(seed uint64)
+}
@@ -0,0 +1,34 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReseed checks that Reseed(seed), after some values have been generated, makes a Reseedable return the
+// same values as one newly-constructed with seed.
+func TestReseed(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		newWithSeed func(seed uint64) Reseedable
+	}{
+		{"SplitMix64", func(seed uint64) Reseedable { return NewSplitMix64(seed) }},
+		{"PCG", func(seed uint64) Reseedable { return NewPCG(seed, 54) }},
+		{"Xoshiro256", func(seed uint64) Reseedable { return NewXoshiro256(seed) }},
+	}
+	for _, tc := range testCases {
+		src := tc.newWithSeed(1)
+		for _, seed := range []uint64{1, 2, 42, 1<<64 - 1} {
+			for i := 0; i < 10; i++ {
+				src.Int63()
+			}
+			src.Reseed(seed)
+			expected := tc.newWithSeed(seed)
+			for i := 0; i < 100; i++ {
+				require.Equal(t, expected.Int63(), src.Int63(), "%s seed=%d i=%d", tc.name, seed, i)
+			}
+		}
+	}
+}
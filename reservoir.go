@@ -0,0 +1,200 @@
+package random
+
+import (
+	"container/heap"
+	"math"
+)
+
+// A Reservoir keeps a uniformly-distributed sample of k items from a stream of items of unknown length.
+//
+// This uses Li's "Algorithm L" (see https://dl.acm.org/doi/10.1145/198429.198435 ), which, instead of
+// drawing a random number for each item like the simpler "Algorithm R", computes how many items to skip
+// before the next item that goes into the sample. This means that the number of draws is O(k(1 + log(N/k)))
+// for a stream of N items, instead of O(N).
+type Reservoir struct {
+	src     Source
+	k       int
+	samples []interface{}
+	// count is the number of items offered so far.
+	count int64
+	// w is the largest of the k random keys in (0, 1] of the items in the sample, if the items in the stream
+	// were each assigned a random key and the items with the smallest k keys were kept.
+	w float64
+	// next is the index of the next item to put into the sample, once the sample is full.
+	next int64
+}
+
+// NewReservoir returns a new Reservoir that keeps a sample of k items, using src. k must be positive.
+func NewReservoir(src Source, k int) *Reservoir {
+	if k <= 0 {
+		panic("k must be positive in call to NewReservoir")
+	}
+
+	return &Reservoir{src: src, k: k, samples: make([]interface{}, 0, k)}
+}
+
+// skip sets r.next to the index of the next item to put into the sample, which is geometrically distributed
+// with success probability r.w.
+func (r *Reservoir) skip() {
+	s := math.Floor(math.Log(openFloat64(r.src)) / math.Log1p(-r.w))
+	// Avoid overflowing if the skip is too large to fit in an int64, which can happen once w is tiny.
+	if s >= float64(math.MaxInt64-r.next-1) {
+		r.next = math.MaxInt64
+		return
+	}
+	r.next += int64(s) + 1
+}
+
+// Offer offers the next item in the stream to r, which either puts it into the sample (possibly replacing
+// another item) or discards it.
+func (r *Reservoir) Offer(item interface{}) {
+	i := r.count
+	r.count++
+
+	if len(r.samples) < r.k {
+		r.samples = append(r.samples, item)
+		if len(r.samples) == r.k {
+			r.w = math.Exp(math.Log(openFloat64(r.src)) / float64(r.k))
+			r.next = i
+			r.skip()
+		}
+		return
+	}
+
+	if i != r.next {
+		return
+	}
+
+	r.samples[Intn(r.src, r.k)] = item
+	r.w *= math.Exp(math.Log(openFloat64(r.src)) / float64(r.k))
+	r.skip()
+}
+
+// Count returns the number of items offered to r so far.
+func (r *Reservoir) Count() int64 {
+	return r.count
+}
+
+// Samples returns a copy of the current sample, which has min(k, r.Count()) items.
+func (r *Reservoir) Samples() []interface{} {
+	samples := make([]interface{}, len(r.samples))
+	copy(samples, r.samples)
+	return samples
+}
+
+// weightedItem is an item in a WeightedReservoir, along with the logarithm of its key.
+type weightedItem struct {
+	item   interface{}
+	logKey float64
+}
+
+// weightedItemHeap is a min-heap of weightedItems ordered by key, implementing heap.Interface.
+type weightedItemHeap []weightedItem
+
+func (h weightedItemHeap) Len() int           { return len(h) }
+func (h weightedItemHeap) Less(i, j int) bool { return h[i].logKey < h[j].logKey }
+func (h weightedItemHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *weightedItemHeap) Push(x interface{}) {
+	*h = append(*h, x.(weightedItem))
+}
+
+func (h *weightedItemHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// A WeightedReservoir keeps a weighted sample of k items without replacement from a stream of items of
+// unknown length, where the probability of each item being in the sample is proportional to its weight.
+//
+// This uses Efraimidis and Spirakis's "A-Res" algorithm (see
+// https://en.wikipedia.org/wiki/Reservoir_sampling#Algorithm_A-Res ), which assigns each item the key u^(1/w),
+// where u is uniformly distributed in (0, 1] and w is its weight, and keeps the k items with the largest keys
+// in a min-heap. To avoid underflow for large weights, the logarithms of the keys, log(u)/w, are compared
+// instead.
+type WeightedReservoir struct {
+	src   Source
+	k     int
+	items weightedItemHeap
+}
+
+// NewWeightedReservoir returns a new WeightedReservoir that keeps a sample of k items, using src. k must be
+// positive.
+func NewWeightedReservoir(src Source, k int) *WeightedReservoir {
+	if k <= 0 {
+		panic("k must be positive in call to NewWeightedReservoir")
+	}
+
+	return &WeightedReservoir{src: src, k: k, items: make(weightedItemHeap, 0, k)}
+}
+
+// Offer offers the next item in the stream to r with the given weight, which must be positive and finite.
+func (r *WeightedReservoir) Offer(item interface{}, weight float64) {
+	if !(weight > 0) || math.IsInf(weight, 1) {
+		panic("weight must be positive and finite in call to WeightedReservoir.Offer")
+	}
+
+	logKey := math.Log(openFloat64(r.src)) / weight
+	if len(r.items) < r.k {
+		heap.Push(&r.items, weightedItem{item, logKey})
+		return
+	}
+
+	if logKey > r.items[0].logKey {
+		r.items[0] = weightedItem{item, logKey}
+		heap.Fix(&r.items, 0)
+	}
+}
+
+// Samples returns the items in the current sample, in no particular order.
+func (r *WeightedReservoir) Samples() []interface{} {
+	samples := make([]interface{}, len(r.items))
+	for i, item := range r.items {
+		samples[i] = item.item
+	}
+	return samples
+}
+
+// WeightedSampleK returns the indices of k distinct items picked without replacement, where at each step the
+// probability of picking a remaining item is proportional to its weight, in no particular order. Items with
+// zero weight are never picked. The weights must be non-negative and finite, k must be in the range 0 to
+// len(weights) (inclusive), and at least k of the weights must be positive.
+//
+// This offers each item with positive weight to a WeightedReservoir, so it uses one call to Float64() per such
+// item.
+func WeightedSampleK(src Source, weights []float64, k int) []int {
+	if k < 0 || k > len(weights) {
+		panic("k must be in [0, len(weights)] in call to WeightedSampleK")
+	}
+	positive := 0
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("weights must be non-negative and finite in call to WeightedSampleK")
+		}
+		if w > 0 {
+			positive++
+		}
+	}
+	if positive < k {
+		panic("at least k weights must be positive in call to WeightedSampleK")
+	}
+
+	if k == 0 {
+		return []int{}
+	}
+
+	r := NewWeightedReservoir(src, k)
+	for i, w := range weights {
+		if w > 0 {
+			r.Offer(i, w)
+		}
+	}
+	samples := r.Samples()
+	out := make([]int, len(samples))
+	for i, item := range samples {
+		out[i] = item.(int)
+	}
+	return out
+}
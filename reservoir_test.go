@@ -0,0 +1,222 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReservoirUniform checks that, for a finite stream of N items, every item has probability k/N of being
+// in the sample.
+func TestReservoirUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	for _, test := range []struct{ k, n int }{{1, 10}, {5, 20}, {10, 200}, {3, 1000}} {
+		src := rand.NewSource(int64(test.k*1000 + test.n))
+		counts := make([]int, test.n)
+		for trial := 0; trial < trials; trial++ {
+			r := NewReservoir(src, test.k)
+			for i := 0; i < test.n; i++ {
+				r.Offer(i)
+			}
+			samples := r.Samples()
+			require.Equal(t, test.k, len(samples))
+			for _, item := range samples {
+				counts[item.(int)]++
+			}
+		}
+		for i, count := range counts {
+			requireBinomialCount(t, trials, float64(test.k)/float64(test.n), count, "k=%d n=%d i=%d", test.k, test.n, i)
+		}
+	}
+}
+
+// TestReservoirShortStream checks that all items are in the sample if fewer than k items are offered.
+func TestReservoirShortStream(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	r := NewReservoir(&src, 5)
+	require.Empty(t, r.Samples())
+	for i := 0; i < 4; i++ {
+		r.Offer(i)
+	}
+	require.Equal(t, []interface{}{0, 1, 2, 3}, r.Samples())
+	require.Equal(t, int64(4), r.Count())
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestReservoirSkips checks that a Reservoir draws far fewer values than the number of items offered for a
+// long stream.
+func TestReservoirSkips(t *testing.T) {
+	t.Parallel()
+	src := NewStatsSource(rand.NewSource(1))
+	r := NewReservoir(src, 10)
+	for i := 0; i < 1000000; i++ {
+		r.Offer(i)
+	}
+	require.Equal(t, int64(1000000), r.Count())
+	require.Less(t, src.Calls(), int64(1000))
+}
+
+// TestReservoirNonPositive checks that NewReservoir() panics for non-positive k.
+func TestReservoirNonPositive(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "k must be positive in call to NewReservoir", func() {
+		NewReservoir(&src, 0)
+	})
+}
+
+// TestWeightedReservoirSingle checks that a WeightedReservoir with k == 1 picks each item with probability
+// proportional to its weight.
+func TestWeightedReservoirSingle(t *testing.T) {
+	t.Parallel()
+	const trials = 50000
+	weights := []float64{1, 2, 3, 4}
+	src := rand.NewSource(1)
+	counts := make([]int, len(weights))
+	for trial := 0; trial < trials; trial++ {
+		r := NewWeightedReservoir(src, 1)
+		for i, w := range weights {
+			r.Offer(i, w)
+		}
+		samples := r.Samples()
+		require.Equal(t, 1, len(samples))
+		counts[samples[0].(int)]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, weights[i]/10, count, "i=%d", i)
+	}
+}
+
+// TestWeightedReservoirHeavy checks that over many stream replays, high-weight items are kept far more often
+// than low-weight ones, and that each sample has k distinct items.
+func TestWeightedReservoirHeavy(t *testing.T) {
+	t.Parallel()
+	const trials = 10000
+	const k = 5
+	const n = 50
+	src := rand.NewSource(2)
+	counts := make([]int, n)
+	for trial := 0; trial < trials; trial++ {
+		r := NewWeightedReservoir(src, k)
+		for i := 0; i < n; i++ {
+			// Every tenth item is heavy.
+			w := 1.0
+			if i%10 == 0 {
+				w = 100
+			}
+			r.Offer(i, w)
+		}
+		seen := make(map[int]bool)
+		for _, item := range r.Samples() {
+			require.False(t, seen[item.(int)])
+			seen[item.(int)] = true
+			counts[item.(int)]++
+		}
+		require.Equal(t, k, len(seen))
+	}
+	for i := 0; i < n; i++ {
+		if i%10 == 0 {
+			require.Greater(t, counts[i], trials*3/4, "i=%d", i)
+		} else {
+			require.Less(t, counts[i], trials/20, "i=%d", i)
+		}
+	}
+}
+
+// TestWeightedReservoirInvalid checks that WeightedReservoir panics for non-positive k or weights.
+func TestWeightedReservoirInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "k must be positive in call to NewWeightedReservoir", func() {
+		NewWeightedReservoir(&src, 0)
+	})
+	r := NewWeightedReservoir(&src, 1)
+	for _, w := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		require.PanicsWithValue(t, "weight must be positive and finite in call to WeightedReservoir.Offer", func() {
+			r.Offer(0, w)
+		}, "w=%v", w)
+	}
+}
+
+// TestWeightedSampleKSingle checks that WeightedSampleK() with k == 1 picks each index with probability
+// proportional to its weight, and never picks an index with zero weight.
+func TestWeightedSampleKSingle(t *testing.T) {
+	t.Parallel()
+	const trials = 50000
+	weights := []float64{1, 0, 3, 4, 0, 2}
+	src := rand.NewSource(3)
+	counts := make([]int, len(weights))
+	for trial := 0; trial < trials; trial++ {
+		s := WeightedSampleK(src, weights, 1)
+		require.Equal(t, 1, len(s))
+		counts[s[0]]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, weights[i]/10, count, "i=%d", i)
+	}
+}
+
+// TestWeightedSampleKMultiple checks that WeightedSampleK() returns k distinct indices, never picks an index
+// with zero weight, and picks higher-weight indices more often.
+func TestWeightedSampleKMultiple(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	const k = 3
+	weights := []float64{1, 0, 2, 4, 8, 0, 16}
+	src := rand.NewSource(4)
+	counts := make([]int, len(weights))
+	for trial := 0; trial < trials; trial++ {
+		s := WeightedSampleK(src, weights, k)
+		require.Equal(t, k, len(s))
+		seen := make(map[int]bool)
+		for _, i := range s {
+			require.False(t, seen[i], "i=%d", i)
+			seen[i] = true
+			counts[i]++
+		}
+	}
+	require.Equal(t, 0, counts[1])
+	require.Equal(t, 0, counts[5])
+	positive := []int{0, 2, 3, 4, 6}
+	for j := 1; j < len(positive); j++ {
+		require.Greater(t, counts[positive[j]], counts[positive[j-1]], "i=%d", positive[j])
+	}
+
+	// If exactly k weights are positive, all of them must be picked.
+	s := WeightedSampleK(src, []float64{0, 1, 0, 5, 2}, k)
+	sort.Ints(s)
+	require.Equal(t, []int{1, 3, 4}, s)
+}
+
+// TestWeightedSampleKZero checks that WeightedSampleK() doesn't use any randomness for k == 0.
+func TestWeightedSampleKZero(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, []int{}, WeightedSampleK(&src, []float64{1, 2}, 0))
+	require.Equal(t, []int{}, WeightedSampleK(&src, nil, 0))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestWeightedSampleKInvalid checks that WeightedSampleK() panics for invalid weights or k.
+func TestWeightedSampleKInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, k := range []int{-1, 3} {
+		require.PanicsWithValue(t, "k must be in [0, len(weights)] in call to WeightedSampleK", func() {
+			WeightedSampleK(&src, []float64{1, 2}, k)
+		}, "k=%d", k)
+	}
+	for _, w := range []float64{-1, math.NaN(), math.Inf(1)} {
+		require.PanicsWithValue(t, "weights must be non-negative and finite in call to WeightedSampleK", func() {
+			WeightedSampleK(&src, []float64{1, w}, 1)
+		}, "w=%v", w)
+	}
+	require.PanicsWithValue(t, "at least k weights must be positive in call to WeightedSampleK", func() {
+		WeightedSampleK(&src, []float64{1, 0, 0}, 2)
+	})
+}
@@ -0,0 +1,237 @@
+package random
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// sampleKSliceRatio is the ratio n/k below which SampleK() uses sampleKSlice() instead of sampleKMap(). A map
+// entry takes up much more space than a slice entry, so sampleKMap() only wins when k is a small fraction of n.
+const sampleKSliceRatio = 8
+
+// SampleK returns k distinct uniformly-distributed numbers in the range 0 to n-1 (inclusive), in random order.
+// k must be at most n.
+//
+// This does the first k steps of a Fisher–Yates shuffle of the numbers 0 to n-1 (inclusive), going up instead
+// of down. If k is small compared to n, only the entries that have been moved are stored (in a map), which
+// takes O(k) space; otherwise, the numbers are stored in a slice, which takes O(n) space but is faster. Both
+// strategies return the same values for the same Source.
+func SampleK(src Source, n, k uint32) []uint32 {
+	if k > n {
+		panic("k must be at most n in call to SampleK")
+	}
+
+	if uint64(k)*sampleKSliceRatio >= uint64(n) {
+		return sampleKSlice(src, n, k)
+	}
+	return sampleKMap(src, n, k)
+}
+
+// UniqueDraws draws k uniformly-distributed numbers in the range 0 to n-1 (inclusive) with replacement, and
+// returns the distinct ones in increasing order. Unlike SampleK(), the number of values returned is random:
+// it's at most min(k, n), and its expected value is n*(1 - (1-1/n)^k). n must be non-zero unless k is zero.
+//
+// Like SampleK(), this uses one of two strategies depending on the ratio of k to n: if k is small compared to
+// n, the draws are sorted and deduplicated, which takes O(k) space; otherwise, they're marked in a slice of n
+// bools, which takes O(n) space. Both strategies return the same values for the same Source.
+func UniqueDraws(src Source, n, k uint32) []uint32 {
+	if n == 0 && k > 0 {
+		panic("n must be non-zero if k is non-zero in call to UniqueDraws")
+	}
+
+	if uint64(k)*sampleKSliceRatio >= uint64(n) {
+		return uniqueDrawsDense(src, n, k)
+	}
+	return uniqueDrawsSparse(src, n, k)
+}
+
+// uniqueDrawsDense is the implementation of UniqueDraws() that marks the draws in a slice of n bools.
+func uniqueDrawsDense(src Source, n, k uint32) []uint32 {
+	seen := make([]bool, n)
+	count := 0
+	for i := uint32(0); i < k; i++ {
+		v := Uint32n(src, n)
+		if !seen[v] {
+			seen[v] = true
+			count++
+		}
+	}
+
+	out := make([]uint32, 0, count)
+	for v, ok := range seen {
+		if ok {
+			out = append(out, uint32(v))
+		}
+	}
+	return out
+}
+
+// uniqueDrawsSparse is the implementation of UniqueDraws() that sorts and deduplicates the draws.
+func uniqueDrawsSparse(src Source, n, k uint32) []uint32 {
+	out := make([]uint32, k)
+	for i := range out {
+		out[i] = Uint32n(src, n)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+
+	j := 0
+	for i, v := range out {
+		if i == 0 || v != out[j-1] {
+			out[j] = v
+			j++
+		}
+	}
+	return out[:j:j]
+}
+
+// SampleKDense returns k distinct uniformly-distributed numbers in the range 0 to n-1 (inclusive), like
+// SampleK(), but in increasing order. k must be at most n.
+//
+// This uses Floyd's algorithm (see Combinations) with a bitset to track the chosen numbers, so it makes
+// exactly k calls to Uint32n() and uses n/8 bytes of scratch space. This is much less memory than the map that
+// SampleK() uses for small k, or the slice of n uint32s that it uses for large k, which makes this a better
+// choice when both n and k are large.
+func SampleKDense(src Source, n, k uint32) []uint32 {
+	if k > n {
+		panic("k must be at most n in call to SampleKDense")
+	}
+
+	chosen := make([]uint64, (uint64(n)+63)/64)
+	has := func(i uint32) bool {
+		return chosen[i/64]&(1<<(i%64)) != 0
+	}
+	for j := n - k; j < n; j++ {
+		t := Uint32n(src, j+1)
+		if has(t) {
+			t = j
+		}
+		chosen[t/64] |= 1 << (t % 64)
+	}
+
+	out := make([]uint32, 0, k)
+	for w, word := range chosen {
+		for word != 0 {
+			b := bits.TrailingZeros64(word)
+			out = append(out, uint32(w*64+b))
+			word &= word - 1
+		}
+	}
+	return out
+}
+
+// sampleKSlice is the implementation of SampleK() that stores all n numbers in a slice.
+func sampleKSlice(src Source, n, k uint32) []uint32 {
+	a := make([]uint32, n)
+	for i := range a {
+		a[i] = uint32(i)
+	}
+	for i := uint32(0); i < k; i++ {
+		j := i + Uint32n(src, n-i)
+		a[i], a[j] = a[j], a[i]
+	}
+	return a[:k:k]
+}
+
+// sampleKMap is the implementation of SampleK() that stores only the moved numbers in a map. If a number i
+// isn't a key in the map, then it's at position i.
+func sampleKMap(src Source, n, k uint32) []uint32 {
+	moved := make(map[uint32]uint32, k)
+	get := func(i uint32) uint32 {
+		if v, ok := moved[i]; ok {
+			return v
+		}
+		return i
+	}
+
+	out := make([]uint32, k)
+	for i := uint32(0); i < k; i++ {
+		j := i + Uint32n(src, n-i)
+		out[i] = get(j)
+		// Position i will never be read again, so only position j needs to be updated.
+		moved[j] = get(i)
+	}
+	return out
+}
+
+// A Combinations draws uniformly-distributed k-subsets of the numbers 0 to n-1 (inclusive) repeatedly, reusing
+// the same memory for each draw.
+//
+// This uses Floyd's algorithm (see https://fermatslibrary.com/s/a-sample-of-brilliance ), which uses exactly k
+// draws from Uint32n() and a set of size k, regardless of n.
+//
+// A Combinations is not safe for concurrent use by multiple goroutines.
+type Combinations struct {
+	n, k   int
+	buf    []int
+	chosen map[int]bool
+}
+
+// NewCombinations returns a new Combinations for k-subsets of the numbers 0 to n-1 (inclusive). n must be
+// non-negative and fit in a uint32, and k must be in the range 0 to n (inclusive).
+func NewCombinations(n, k int) *Combinations {
+	if n < 0 || uint64(n) > math.MaxUint32 {
+		panic("n must be non-negative and fit in a uint32 in call to NewCombinations")
+	}
+
+	if k < 0 || k > n {
+		panic("k must be in [0, n] in call to NewCombinations")
+	}
+
+	return &Combinations{n: n, k: k, buf: make([]int, k), chosen: make(map[int]bool, k)}
+}
+
+// Sample returns a uniformly-distributed k-subset of the numbers 0 to n-1 (inclusive), in no particular order.
+// The returned slice is reused by the next call to Sample, so it's only valid until then.
+func (c *Combinations) Sample(src Source) []int {
+	for i := range c.chosen {
+		delete(c.chosen, i)
+	}
+
+	// For each j from n-k to n-1, add a random number in [0, j] to the subset, or j itself if that number is
+	// already in the subset.
+	for i, j := 0, c.n-c.k; j < c.n; i, j = i+1, j+1 {
+		t := int(Uint32n(src, uint32(j+1)))
+		if c.chosen[t] {
+			t = j
+		}
+		c.chosen[t] = true
+		c.buf[i] = t
+	}
+	return c.buf
+}
+
+// Uint32nPair returns two distinct uniformly-distributed numbers in the range 0 to n-1 (inclusive). n must be
+// at least 2.
+//
+// The first number a is drawn from [0, n), and the second one is drawn with Uint32nExcept(src, n, a).
+func Uint32nPair(src Source, n uint32) (uint32, uint32) {
+	if n < 2 {
+		panic("n must be at least 2 in call to Uint32nPair")
+	}
+
+	a := Uint32n(src, n)
+	return a, Uint32nExcept(src, n, a)
+}
+
+// Uint32nExcept returns a uniformly-distributed number in the range 0 to n-1 (inclusive) that isn't equal to
+// exclude. n must be at least 2, and exclude must be less than n.
+//
+// This draws from [0, n-1) and then increments the result if it's at least exclude, which maps [0, n-1) onto
+// [0, n) with exclude removed, so no rejection loop is needed.
+func Uint32nExcept(src Source, n, exclude uint32) uint32 {
+	if n < 2 {
+		panic("n must be at least 2 in call to Uint32nExcept")
+	}
+	if exclude >= n {
+		panic("exclude must be less than n in call to Uint32nExcept")
+	}
+
+	v := Uint32n(src, n-1)
+	if v >= exclude {
+		v++
+	}
+	return v
+}
@@ -0,0 +1,372 @@
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSampleKStrategiesMatch checks that sampleKSlice() and sampleKMap() return the same values for the same
+// Source.
+func TestSampleKStrategiesMatch(t *testing.T) {
+	t.Parallel()
+	for n := uint32(0); n < 50; n++ {
+		for k := uint32(0); k <= n; k++ {
+			expected := sampleKSlice(rand.NewSource(int64(n*100+k)), n, k)
+			actual := sampleKMap(rand.NewSource(int64(n*100+k)), n, k)
+			require.Equal(t, expected, actual, "n=%d k=%d", n, k)
+		}
+	}
+}
+
+// TestSampleKDistinct checks that SampleK() returns k distinct values in range, for values of k that use
+// either strategy.
+func TestSampleKDistinct(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, n := range []uint32{0, 1, 2, 10, 100, 10000} {
+		for _, k := range []uint32{0, 1, n / 100, n / 10, n / 2, n} {
+			if k > n {
+				continue
+			}
+			s := SampleK(src, n, k)
+			require.Equal(t, int(k), len(s), "n=%d k=%d", n, k)
+			seen := make(map[uint32]bool)
+			for _, v := range s {
+				require.Less(t, v, n, "n=%d k=%d", n, k)
+				require.False(t, seen[v], "n=%d k=%d v=%d", n, k, v)
+				seen[v] = true
+			}
+		}
+	}
+}
+
+// TestSampleKUniform checks that over many runs, each element of [0, n) is selected with probability k/n,
+// for values of k that use either strategy.
+func TestSampleKUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	const n = 100
+	for _, k := range []uint32{1, 5, 50} {
+		src := rand.NewSource(int64(k))
+		var counts [n]int
+		for trial := 0; trial < trials; trial++ {
+			for _, v := range SampleK(src, n, k) {
+				counts[v]++
+			}
+		}
+		for v, count := range counts {
+			requireBinomialCount(t, trials, float64(k)/n, count, "k=%d v=%d", k, v)
+		}
+	}
+}
+
+// TestSampleKTooLarge checks that SampleK() panics if k > n.
+func TestSampleKTooLarge(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "k must be at most n in call to SampleK", func() {
+		SampleK(&src, 3, 4)
+	})
+}
+
+// TestCombinationsUniform checks that Combinations.Sample() returns each k-subset with equal probability, for
+// small n and k, by exhaustively counting.
+func TestCombinationsUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 50000
+	for _, test := range []struct{ n, k, subsetCount int }{{5, 2, 10}, {6, 3, 20}, {4, 4, 1}, {4, 1, 4}, {3, 0, 1}} {
+		c := NewCombinations(test.n, test.k)
+		src := rand.NewSource(int64(test.n*10 + test.k))
+		counts := make(map[string]int)
+		for trial := 0; trial < trials; trial++ {
+			s := c.Sample(src)
+			require.Equal(t, test.k, len(s))
+			sorted := append([]int(nil), s...)
+			sort.Ints(sorted)
+			for i, v := range sorted {
+				require.True(t, v >= 0 && v < test.n, "v=%d", v)
+				if i > 0 {
+					require.NotEqual(t, sorted[i-1], v)
+				}
+			}
+			counts[fmt.Sprint(sorted)]++
+		}
+		require.Equal(t, test.subsetCount, len(counts), "n=%d k=%d", test.n, test.k)
+		for subset, count := range counts {
+			requireBinomialCount(t, trials, 1/float64(test.subsetCount), count, "n=%d k=%d subset=%s", test.n, test.k, subset)
+		}
+	}
+}
+
+// TestCombinationsReuse checks that Combinations.Sample() reuses its buffer, and uses exactly k draws.
+func TestCombinationsReuse(t *testing.T) {
+	t.Parallel()
+	c := NewCombinations(1000, 10)
+	src := NewStatsSource(rand.NewSource(1))
+	s1 := c.Sample(src)
+	require.Equal(t, int64(10), src.Calls())
+	s2 := c.Sample(src)
+	require.Equal(t, int64(20), src.Calls())
+	require.Equal(t, &s1[0], &s2[0])
+}
+
+// TestCombinationsInvalid checks that NewCombinations() panics for invalid n or k.
+func TestCombinationsInvalid(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "n must be non-negative and fit in a uint32 in call to NewCombinations", func() {
+		NewCombinations(-1, 0)
+	})
+	require.PanicsWithValue(t, "k must be in [0, n] in call to NewCombinations", func() {
+		NewCombinations(3, 4)
+	})
+}
+
+// TestUint32nPair checks that the two values returned by Uint32nPair() are never equal, and that, conditioned
+// on the first, the second is uniform over the other n-1 values.
+func TestUint32nPair(t *testing.T) {
+	t.Parallel()
+	const n = 5
+	const trials = 100000
+	src := rand.NewSource(1)
+	var counts [n][n]int
+	var firstCounts [n]int
+	for i := 0; i < trials; i++ {
+		a, b := Uint32nPair(src, n)
+		require.NotEqual(t, a, b)
+		require.Less(t, a, uint32(n))
+		require.Less(t, b, uint32(n))
+		counts[a][b]++
+		firstCounts[a]++
+	}
+	for a := 0; a < n; a++ {
+		requireBinomialCount(t, trials, 1.0/n, firstCounts[a], "a=%d", a)
+		for b := 0; b < n; b++ {
+			if a == b {
+				continue
+			}
+			requireBinomialCount(t, firstCounts[a], 1.0/(n-1), counts[a][b], "a=%d b=%d", a, b)
+		}
+	}
+}
+
+// TestUint32nPairSmall checks that Uint32nPair() panics for n < 2.
+func TestUint32nPairSmall(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, n := range []uint32{0, 1} {
+		require.PanicsWithValue(t, "n must be at least 2 in call to Uint32nPair", func() {
+			Uint32nPair(&src, n)
+		}, "n=%d", n)
+	}
+}
+
+// TestUint32nExcept checks that Uint32nExcept() never returns exclude, and returns the other values
+// uniformly.
+func TestUint32nExcept(t *testing.T) {
+	t.Parallel()
+	const n = 6
+	const trials = 60000
+	src := rand.NewSource(1)
+	for exclude := uint32(0); exclude < n; exclude++ {
+		var counts [n]int
+		for i := 0; i < trials; i++ {
+			v := Uint32nExcept(src, n, exclude)
+			require.Less(t, v, uint32(n))
+			counts[v]++
+		}
+		require.Equal(t, 0, counts[exclude], "exclude=%d", exclude)
+		for v := uint32(0); v < n; v++ {
+			if v == exclude {
+				continue
+			}
+			requireBinomialCount(t, trials, 1.0/(n-1), counts[v], "exclude=%d v=%d", exclude, v)
+		}
+	}
+}
+
+// TestUint32nExceptTwo checks that Uint32nExcept() deterministically returns the other value for n == 2.
+func TestUint32nExceptTwo(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for i := 0; i < 100; i++ {
+		require.Equal(t, uint32(1), Uint32nExcept(src, 2, 0))
+		require.Equal(t, uint32(0), Uint32nExcept(src, 2, 1))
+	}
+}
+
+// TestUint32nExceptInvalid checks that Uint32nExcept() panics for n < 2 or exclude >= n.
+func TestUint32nExceptInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, n := range []uint32{0, 1} {
+		require.PanicsWithValue(t, "n must be at least 2 in call to Uint32nExcept", func() {
+			Uint32nExcept(&src, n, 0)
+		}, "n=%d", n)
+	}
+	for _, exclude := range []uint32{5, 6, math.MaxUint32} {
+		require.PanicsWithValue(t, "exclude must be less than n in call to Uint32nExcept", func() {
+			Uint32nExcept(&src, 5, exclude)
+		}, "exclude=%d", exclude)
+	}
+}
+
+// TestUniqueDrawsStrategiesMatch checks that uniqueDrawsDense() and uniqueDrawsSparse() return the same
+// values for the same Source.
+func TestUniqueDrawsStrategiesMatch(t *testing.T) {
+	t.Parallel()
+	for n := uint32(1); n < 50; n++ {
+		for k := uint32(0); k <= 2*n; k++ {
+			expected := uniqueDrawsDense(rand.NewSource(int64(n*100+k)), n, k)
+			actual := uniqueDrawsSparse(rand.NewSource(int64(n*100+k)), n, k)
+			require.Equal(t, expected, actual, "n=%d k=%d", n, k)
+		}
+	}
+}
+
+// TestUniqueDrawsSorted checks that UniqueDraws() returns at most min(k, n) values in range, in strictly
+// increasing order.
+func TestUniqueDrawsSorted(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, n := range []uint32{1, 2, 10, 1000} {
+		for _, k := range []uint32{0, 1, n / 10, n, 10 * n} {
+			s := UniqueDraws(src, n, k)
+			require.LessOrEqual(t, len(s), int(k), "n=%d k=%d", n, k)
+			require.LessOrEqual(t, len(s), int(n), "n=%d k=%d", n, k)
+			for i, v := range s {
+				require.Less(t, v, n, "n=%d k=%d", n, k)
+				if i > 0 {
+					require.Less(t, s[i-1], v, "n=%d k=%d", n, k)
+				}
+			}
+		}
+	}
+}
+
+// TestUniqueDrawsCount checks that the average number of values returned by UniqueDraws() matches the
+// coupon-collector formula n*(1 - (1-1/n)^k).
+func TestUniqueDrawsCount(t *testing.T) {
+	t.Parallel()
+	const trials = 10000
+	testCases := []struct{ n, k uint32 }{
+		{10, 5},
+		{10, 30},
+		{1000, 50},
+		{1000, 1000},
+	}
+	for _, tc := range testCases {
+		src := rand.NewSource(int64(tc.n + tc.k))
+		mean, _ := sampleMoments(trials, func() float64 {
+			return float64(len(UniqueDraws(src, tc.n, tc.k)))
+		})
+		n := float64(tc.n)
+		k := float64(tc.k)
+		p1 := math.Pow(1-1/n, k)
+		p2 := math.Pow(1-2/n, k)
+		expectedMean := n * (1 - p1)
+		expectedVariance := n*(n-1)*p2 + n*p1 - n*n*p1*p1
+		require.InDelta(t, expectedMean, mean, 5*math.Sqrt(expectedVariance/trials), "n=%d k=%d", tc.n, tc.k)
+	}
+}
+
+// TestUniqueDrawsInvalid checks that UniqueDraws() returns an empty slice for k == 0, even if n == 0, and
+// panics for n == 0 otherwise.
+func TestUniqueDrawsInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Empty(t, UniqueDraws(&src, 0, 0))
+	require.Equal(t, 0, src.callCount)
+	require.PanicsWithValue(t, "n must be non-zero if k is non-zero in call to UniqueDraws", func() {
+		UniqueDraws(&src, 0, 1)
+	})
+}
+
+// TestSampleKDense checks that SampleKDense() returns k distinct values in range, in increasing order.
+func TestSampleKDense(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, n := range []uint32{0, 1, 2, 63, 64, 65, 1000} {
+		for _, k := range []uint32{0, 1, n / 2, n - 1, n} {
+			if k > n {
+				continue
+			}
+			s := SampleKDense(src, n, k)
+			require.Equal(t, int(k), len(s), "n=%d k=%d", n, k)
+			for i, v := range s {
+				require.Less(t, v, n, "n=%d k=%d", n, k)
+				if i > 0 {
+					require.Less(t, s[i-1], v, "n=%d k=%d", n, k)
+				}
+			}
+		}
+	}
+}
+
+// TestSampleKDenseUniform checks that each number is included in the output of SampleKDense() with
+// probability k/n, and that each k-subset comes up equally often.
+func TestSampleKDenseUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const n = 6
+	const k = 3
+	src := rand.NewSource(2)
+	var counts [n]int
+	subsetCounts := make(map[string]int)
+	for i := 0; i < trials; i++ {
+		s := SampleKDense(src, n, k)
+		for _, v := range s {
+			counts[v]++
+		}
+		subsetCounts[fmt.Sprint(s)]++
+	}
+	for v, count := range counts {
+		requireBinomialCount(t, trials, float64(k)/n, count, "v=%d", v)
+	}
+	// There are 6 choose 3 = 20 subsets.
+	require.Equal(t, 20, len(subsetCounts))
+	for subset, count := range subsetCounts {
+		requireBinomialCount(t, trials, 1.0/20, count, "subset=%s", subset)
+	}
+}
+
+// TestSampleKDenseInvalid checks that SampleKDense() panics for k > n.
+func TestSampleKDenseInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "k must be at most n in call to SampleKDense", func() {
+		SampleKDense(&src, 5, 6)
+	})
+}
+
+// The BenchmarkSampleKDense* functions benchmark SampleKDense() against sampleKMap() (the map-based
+// strategy of SampleK()) for a large n and k == n/2, reporting the number of bytes allocated per call.
+//
+// In my runs with n = 2²², SampleKDense() was about 10 times faster than sampleKMap() and allocated about a
+// fifth as much, since it only needs one bit per number instead of a map entry per chosen number.
+
+const sampleKDenseN = 1 << 22
+
+var sampleKDenseResult []uint32
+
+func BenchmarkSampleKDense(b *testing.B) {
+	b.ReportAllocs()
+	src := rand.NewSource(17)
+	for n := 0; n < b.N; n++ {
+		sampleKDenseResult = SampleKDense(src, sampleKDenseN, sampleKDenseN/2)
+	}
+}
+
+var sampleKDenseMapResult []uint32
+
+func BenchmarkSampleKDenseMap(b *testing.B) {
+	b.ReportAllocs()
+	src := rand.NewSource(17)
+	for n := 0; n < b.N; n++ {
+		sampleKDenseMapResult = sampleKMap(src, sampleKDenseN, sampleKDenseN/2)
+	}
+}
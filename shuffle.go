@@ -0,0 +1,466 @@
+package random
+
+import (
+	"context"
+	"math/bits"
+)
+
+// Shuffle pseudo-randomizes the order of elements using a Fisher–Yates shuffle. n is the number of elements,
+// and must be non-negative. swap swaps the elements with indexes i and j.
+//
+// This is a copy of rand.Shuffle() that uses Uint32n() instead of rand.int31n(), and Uint64n() instead of
+// rand.Int63n(); see the benchmarks in random_test.go.
+func Shuffle(src Source, n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("n must be non-negative in call to Shuffle")
+	}
+
+	// Like rand.Shuffle(), use the 64-bit path for the (rare) case where i+1 doesn't fit in 31 bits.
+	// Unlike rand.Int63n(), which always does a remainder operation (and sometimes two), Uint64n() usually
+	// doesn't do any.
+	i := n - 1
+	for ; i > 1<<31-1-1; i-- {
+		j := int(Uint64n(src, uint64(i+1)))
+		swap(i, j)
+	}
+	for ; i > 0; i-- {
+		j := int(Uint32n(src, uint32(i+1)))
+		swap(i, j)
+	}
+}
+
+// ShuffleRange pseudo-randomizes the order of the elements with indexes start to end-1 (inclusive), making the
+// same swaps (offset by start) that Shuffle(src, end-start, swap) would. swap is called with absolute
+// indexes, so it can close over the full slice. start must be non-negative, and end must be at least start.
+func ShuffleRange(src Source, start, end int, swap func(i, j int)) {
+	if start < 0 {
+		panic("start must be non-negative in call to ShuffleRange")
+	}
+	if end < start {
+		panic("end must be at least start in call to ShuffleRange")
+	}
+
+	Shuffle(src, end-start, func(i, j int) {
+		swap(start+i, start+j)
+	})
+}
+
+// shuffleContextCheckInterval is the number of swaps that ShuffleContext() makes between checks of its
+// context. Checking is cheap, but not free, so this keeps the overhead negligible while still responding to
+// cancellation quickly.
+const shuffleContextCheckInterval = 4096
+
+// ShuffleContext pseudo-randomizes the order of elements like Shuffle(), making the same swaps, but checks ctx
+// every 4096 swaps (and before the first one), and stops early and returns ctx.Err() if ctx is done. This is
+// useful for shuffling huge numbers of elements on behalf of a request that may be cancelled. If ctx is never
+// done, this returns nil. n is the number of elements, and must be non-negative. swap swaps the elements with
+// indexes i and j.
+//
+// If this returns early, the elements are left partially shuffled.
+func ShuffleContext(ctx context.Context, src Source, n int, swap func(i, j int)) error {
+	if n < 0 {
+		panic("n must be non-negative in call to ShuffleContext")
+	}
+
+	for i := n - 1; i > 0; {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		stop := i - shuffleContextCheckInterval
+		if stop < 0 {
+			stop = 0
+		}
+		for ; i > stop; i-- {
+			// See Shuffle() for the 64-bit path.
+			var j int
+			if i > 1<<31-1-1 {
+				j = int(Uint64n(src, uint64(i+1)))
+			} else {
+				j = int(Uint32n(src, uint32(i+1)))
+			}
+			swap(i, j)
+		}
+	}
+	return nil
+}
+
+// ShuffleFrugal pseudo-randomizes the order of elements like Shuffle(), but consumes fewer random bits, which
+// is useful when src is slow or entropy is scarce. n is the number of elements, and must be non-negative. swap
+// swaps the elements with indexes i and j, and is called in the same order as by Shuffle(), although with
+// different values of j.
+//
+// Instead of drawing one bounded value per step, this uses the batched method from Brackett-Rozinsky and
+// Lemire's "Batched Ranged Random Integer Generation" (2024): consecutive bounds i+1, i, ... are grouped so
+// that their product P fits in 64 bits, and then a single 64-bit value r yields all of the indices for the
+// group, by repeatedly taking the high 64 bits of r*bound as the index and the low 64 bits as the new r. As
+// with Uint64n(), the result is exactly uniform if the final r is at least 2⁶⁴ % P, and otherwise r is
+// rejected and redrawn.
+//
+// For small n, each group covers many steps: e.g., shuffling 52 elements takes about 5 values from
+// randUint64(), compared to 51 calls to Uint32n() for Shuffle(). Even for a Source that isn't a Source64 (like
+// a StatsSource), where each value takes two calls to Int63(), that's still about 5 times fewer calls. The
+// savings shrink as n grows, since fewer bounds fit in each group; for n around 10⁵, this makes about 40%
+// fewer calls to Int63() than Shuffle(). See the BenchmarkShuffleFrugal* functions in shuffle_test.go.
+func ShuffleFrugal(src Source, n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("n must be non-negative in call to ShuffleFrugal")
+	}
+
+	// Each bound is at least 2, so at most 63 of them fit in a batch.
+	var js [63]uint64
+	i := n - 1
+	for i > 0 {
+		// Group the bounds i+1, i, ..., i-k+2 so that their product fits in 64 bits.
+		product := uint64(i + 1)
+		k := 1
+		for k < i {
+			hi, lo := bits.Mul64(product, uint64(i-k+1))
+			if hi != 0 {
+				break
+			}
+			product = lo
+			k++
+		}
+
+		uint64nBatch(src, uint64(i+1), product, js[:k])
+		for m := 0; m < k; m++ {
+			swap(i-m, int(js[m]))
+		}
+		i -= k
+	}
+}
+
+// uint64nBatch sets out[m] to a uniformly-distributed number in the range 0 to bound-m-1 (inclusive) for each
+// m, using as few calls to randUint64() as possible. product must be the product of the bounds; see
+// ShuffleFrugal() for details.
+func uint64nBatch(src Source, bound, product uint64, out []uint64) {
+	var threshold uint64
+	thresholdComputed := false
+	for {
+		r := randUint64(src)
+		for m := range out {
+			out[m], r = bits.Mul64(r, bound-uint64(m))
+		}
+
+		// As in Uint32n(), the threshold is 2⁶⁴ % product < product, so it's only computed if needed.
+		if r >= product {
+			return
+		}
+		if !thresholdComputed {
+			threshold = -product % product
+			thresholdComputed = true
+		}
+		if r >= threshold {
+			return
+		}
+	}
+}
+
+// ShuffleN partially shuffles n elements so that the first k positions hold a uniformly-distributed random
+// sample of k of the n elements, in random order; the order of the other n-k elements is unspecified. n must
+// be non-negative, and k must be in the range 0 to n (inclusive). swap swaps the elements with indexes i and j.
+//
+// This does the first k steps of a Fisher–Yates shuffle going up instead of down, so it makes at most k swaps
+// instead of n-1.
+func ShuffleN(src Source, n, k int, swap func(i, j int)) {
+	if n < 0 {
+		panic("n must be non-negative in call to ShuffleN")
+	}
+
+	if k < 0 || k > n {
+		panic("k must be in [0, n] in call to ShuffleN")
+	}
+
+	// The last element is already in place once the others are, so skip it.
+	if k == n && k > 0 {
+		k--
+	}
+	for i := 0; i < k; i++ {
+		swap(i, forwardShuffleIndex(src, i, n))
+	}
+}
+
+// forwardShuffleIndex returns a uniformly-distributed number in the range i to n-1 (inclusive), which is the
+// index to swap with i in step i of a Fisher–Yates shuffle going up.
+func forwardShuffleIndex(src Source, i, n int) int {
+	m := n - i
+	if m > 1<<31-1 {
+		return i + int(Uint64n(src, uint64(m)))
+	}
+	return i + int(Uint32n(src, uint32(m)))
+}
+
+// A ShuffleIterator returns the elements of a pseudo-random permutation of the integers 0 to n-1 (inclusive)
+// one at a time, doing one step of a Fisher–Yates shuffle going up per element, so that a caller that stops
+// early doesn't pay for shuffling the rest. It uses O(n) memory and O(1) time per element.
+//
+// The first k elements returned are the same as the first k elements of the identity permutation after
+// ShuffleN(src, n, k, swap) with the same state of src.
+type ShuffleIterator struct {
+	src Source
+	a   []int
+	i   int
+}
+
+// NewShuffleIterator returns a new ShuffleIterator for a permutation of the integers 0 to n-1 (inclusive),
+// which draws from src. n must be non-negative.
+func NewShuffleIterator(src Source, n int) *ShuffleIterator {
+	if n < 0 {
+		panic("n must be non-negative in call to NewShuffleIterator")
+	}
+
+	a := make([]int, n)
+	for i := range a {
+		a[i] = i
+	}
+	return &ShuffleIterator{src: src, a: a}
+}
+
+// Next returns the next element of the permutation and true, or 0 and false if all n elements have already
+// been returned.
+func (it *ShuffleIterator) Next() (int, bool) {
+	i := it.i
+	n := len(it.a)
+	if i >= n {
+		return 0, false
+	}
+
+	// As in ShuffleN(), the last element is already in place once the others are, so don't draw for it.
+	if i < n-1 {
+		j := forwardShuffleIndex(it.src, i, n)
+		it.a[i], it.a[j] = it.a[j], it.a[i]
+	}
+	it.i++
+	return it.a[i], true
+}
+
+// ShuffleSlice pseudo-randomizes the order of the elements of s, making the same swaps that
+// Shuffle(src, len(s), swap) would.
+//
+// Besides not calling a swap function, this inlines the loop from Uint32n(), and if src is a Source64 (but not
+// a source32), it checks that only once instead of on every draw, which makes it about twice as fast as
+// Shuffle() for large slices; see the BenchmarkShuffleSlice* functions in shuffle_test.go.
+func ShuffleSlice[T any](src Source, s []T) {
+	i := len(s) - 1
+	for ; i > 1<<31-1-1; i-- {
+		j := int(Uint64n(src, uint64(i+1)))
+		s[i], s[j] = s[j], s[i]
+	}
+
+	// The loops below are the same as the one in Uint32nErr(), with randUint32(src) replaced by the top 32
+	// bits of src.Uint64() for the first one, so they return the same values as Uint32n(src, i+1) would.
+	if _, ok := src.(source32); !ok {
+		if src64, ok := src.(Source64); ok {
+			for ; i > 0; i-- {
+				n := uint32(i + 1)
+				prod := uint64(uint32(src64.Uint64()>>32)) * uint64(n)
+				if low := uint32(prod); low < n {
+					threshold := -n % n
+					for low < threshold {
+						prod = uint64(uint32(src64.Uint64()>>32)) * uint64(n)
+						low = uint32(prod)
+					}
+				}
+				j := int(prod >> 32)
+				s[i], s[j] = s[j], s[i]
+			}
+			return
+		}
+	}
+
+	for ; i > 0; i-- {
+		n := uint32(i + 1)
+		prod := uint64(randUint32(src)) * uint64(n)
+		if low := uint32(prod); low < n {
+			threshold := -n % n
+			for low < threshold {
+				prod = uint64(randUint32(src)) * uint64(n)
+				low = uint32(prod)
+			}
+		}
+		j := int(prod >> 32)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// ShuffleBytes pseudo-randomizes the order of the bytes in b, for hot paths that scramble byte buffers. It has
+// the same distribution as ShuffleSlice(src, b), and swaps the same positions i in the same order, but if src is
+// a Source64 (and not a source32), it takes two indices from each call to src.Uint64(), one from each half,
+// instead of one, so the values of j differ. Otherwise, it just calls ShuffleSlice(src, b).
+//
+// This makes it about a third faster than ShuffleSlice(src, b) for a Source64 like the one returned by
+// rand.NewSource(); see the BenchmarkShuffleBytes* functions in shuffle_test.go.
+func ShuffleBytes(src Source, b []byte) {
+	src64, ok := src.(Source64)
+	if _, is32 := src.(source32); !ok || is32 {
+		ShuffleSlice(src, b)
+		return
+	}
+
+	i := len(b) - 1
+	for ; i > 1<<31-1-1; i-- {
+		j := int(Uint64n(src, uint64(i+1)))
+		b[i], b[j] = b[j], b[i]
+	}
+	// uint32nFrom() is too big to be inlined, so its body is copied here for each half of v.
+	for ; i > 1; i -= 2 {
+		v := src64.Uint64()
+
+		n := uint32(i + 1)
+		prod := uint64(uint32(v>>32)) * uint64(n)
+		if low := uint32(prod); low < n {
+			threshold := -n % n
+			for low < threshold {
+				prod = uint64(uint32(src64.Uint64()>>32)) * uint64(n)
+				low = uint32(prod)
+			}
+		}
+		j := prod >> 32
+		b[i], b[j] = b[j], b[i]
+
+		n--
+		prod = uint64(uint32(v)) * uint64(n)
+		if low := uint32(prod); low < n {
+			threshold := -n % n
+			for low < threshold {
+				prod = uint64(uint32(src64.Uint64()>>32)) * uint64(n)
+				low = uint32(prod)
+			}
+		}
+		j = prod >> 32
+		b[i-1], b[j] = b[j], b[i-1]
+	}
+	if i == 1 {
+		j := uint32nFrom(src64, uint32(src64.Uint64()>>32), 2)
+		b[1], b[j] = b[j], b[1]
+	}
+}
+
+// uint32nFrom returns a uniformly-distributed number in the range 0 to n-1 (inclusive), like Uint32n(), except
+// that v is used as the first value instead of drawing it from src. Any further values are the top 32 bits of
+// src.Uint64(). n must be non-zero.
+func uint32nFrom(src Source64, v, n uint32) uint32 {
+	// This is the loop from Uint32nErr(), with the first iteration pulled out.
+	prod := uint64(v) * uint64(n)
+	if low := uint32(prod); low < n {
+		threshold := -n % n
+		for low < threshold {
+			prod = uint64(uint32(src.Uint64()>>32)) * uint64(n)
+			low = uint32(prod)
+		}
+	}
+	return uint32(prod >> 32)
+}
+
+// ShuffleString returns a new string with the runes (i.e., Unicode code points) of s in a pseudo-random order,
+// making the same swaps on them that ShuffleSlice(src, []rune(s)) would. The result is always valid UTF-8, even
+// if s isn't, since each invalid byte in s is replaced with utf8.RuneError, as in []rune(s).
+//
+// Note that this permutes code points, not user-perceived characters: e.g., a combining accent may end up on a
+// different letter, and an emoji made of multiple code points (like a flag, or a sequence joined with U+200D)
+// is split apart.
+func ShuffleString(src Source, s string) string {
+	r := []rune(s)
+	ShuffleSlice(src, r)
+	return string(r)
+}
+
+// Perm returns a pseudo-random permutation of the integers 0 to n-1 (inclusive). n must be non-negative.
+//
+// Unlike rand.Perm(), which builds the permutation with an "inside-out" shuffle, this fills in the
+// identity permutation and then calls Shuffle() on it.
+func Perm(src Source, n int) []int {
+	if n < 0 {
+		panic("n must be non-negative in call to Perm")
+	}
+
+	m := make([]int, n)
+	for i := range m {
+		m[i] = i
+	}
+	Shuffle(src, n, func(i, j int) {
+		m[i], m[j] = m[j], m[i]
+	})
+	return m
+}
+
+// PermInsideOut returns a pseudo-random permutation of the integers 0 to n-1 (inclusive), like Perm(), but
+// builds it with an "inside-out" Fisher–Yates shuffle, like rand.Perm(), which fills in and shuffles the
+// permutation in a single pass, with element i placed at step i. n must be non-negative.
+//
+// This saves the separate pass that Perm() makes to fill in the identity permutation; see the
+// BenchmarkPermInsideOut* functions in shuffle_test.go. It returns different permutations than Perm() for the
+// same state of src.
+func PermInsideOut(src Source, n int) []int {
+	if n < 0 {
+		panic("n must be non-negative in call to PermInsideOut")
+	}
+
+	m := make([]int, n)
+	i := 0
+	for ; i < n && i < 1<<31-1; i++ {
+		j := int(Uint32n(src, uint32(i+1)))
+		m[i] = m[j]
+		m[j] = i
+	}
+	for ; i < n; i++ {
+		j := int(Uint64n(src, uint64(i+1)))
+		m[i] = m[j]
+		m[j] = i
+	}
+	return m
+}
+
+// PermuteIndices returns the mapping that Shuffle(src, n, swap) would apply, as a permutation p of the integers
+// 0 to n-1 (inclusive) where p[i] is the index that the element at index i would be moved to. n must be
+// non-negative.
+//
+// This is the inverse of the permutation returned by Perm(src, n), which instead gives, for each index, the
+// element moved there; i.e., PermuteIndices(src, n) == Invert(Perm(src, n)). To undo the shuffle, apply
+// Invert(p) in the same way, i.e. move the element at index i to index Invert(p)[i].
+func PermuteIndices(src Source, n int) []int {
+	if n < 0 {
+		panic("n must be non-negative in call to PermuteIndices")
+	}
+
+	return Invert(Perm(src, n))
+}
+
+// PermutationMatrix returns a pseudo-random n×n permutation matrix, i.e. a matrix with exactly one 1 in each row
+// and column and 0s everywhere else. n must be non-negative.
+//
+// The matrix m is built from p := Perm(src, n), with m[i][p[i]] = 1, so that multiplying m by a vector x gives
+// the vector whose ith element is x[p[i]]. The rows share a single backing array.
+func PermutationMatrix(src Source, n int) [][]float64 {
+	if n < 0 {
+		panic("n must be non-negative in call to PermutationMatrix")
+	}
+
+	p := Perm(src, n)
+	backing := make([]float64, n*n)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = backing[i*n : (i+1)*n : (i+1)*n]
+		m[i][p[i]] = 1
+	}
+	return m
+}
+
+// Invert returns the inverse of the permutation p, i.e. the permutation q such that q[p[i]] == i for all i. p
+// must be a permutation of the integers 0 to len(p)-1 (inclusive).
+func Invert(p []int) []int {
+	q := make([]int, len(p))
+	for i := range q {
+		q[i] = -1
+	}
+	for i, v := range p {
+		if v < 0 || v >= len(p) || q[v] != -1 {
+			panic("p must be a permutation in call to Invert")
+		}
+		q[v] = i
+	}
+	return q
+}
@@ -0,0 +1,29 @@
+package random
+
+// UniformSimplex returns a uniformly-distributed pseudo-random point on the (dim-1)-dimensional probability
+// simplex, i.e. dim non-negative values that sum to 1. (This is the same as a draw from the Dirichlet
+// distribution with all parameters equal to 1.) dim must be positive.
+//
+// This uses the standard method of drawing dim exponentially-distributed values and dividing each one by their
+// sum. For dim == 1, this just returns {1.0} without using any randomness.
+func UniformSimplex(src Source, dim int) []float64 {
+	if dim < 1 {
+		panic("dim must be positive in call to UniformSimplex")
+	}
+
+	x := make([]float64, dim)
+	if dim == 1 {
+		x[0] = 1
+		return x
+	}
+
+	var sum float64
+	for i := range x {
+		x[i] = expFloat64(src)
+		sum += x[i]
+	}
+	for i := range x {
+		x[i] /= sum
+	}
+	return x
+}
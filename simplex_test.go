@@ -0,0 +1,71 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUniformSimplexSum checks that the components returned by UniformSimplex() are non-negative and sum to
+// 1, within floating-point tolerance.
+func TestUniformSimplexSum(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for dim := 1; dim <= 20; dim++ {
+		for i := 0; i < 100; i++ {
+			x := UniformSimplex(src, dim)
+			require.Equal(t, dim, len(x))
+			var sum float64
+			for _, xi := range x {
+				require.True(t, xi >= 0, "xi=%v", xi)
+				sum += xi
+			}
+			require.InDelta(t, 1, sum, 1e-12, "dim=%d", dim)
+		}
+	}
+}
+
+// TestUniformSimplexMarginals checks that the marginal mean of each component is about 1/dim, and that the
+// marginal variance matches the Dirichlet(1, ..., 1) value (dim-1)/(dim²(dim+1)).
+func TestUniformSimplexMarginals(t *testing.T) {
+	t.Parallel()
+	const trials = 50000
+	for _, dim := range []int{2, 3, 10} {
+		src := rand.NewSource(int64(dim))
+		sums := make([]float64, dim)
+		sumSquares := make([]float64, dim)
+		for i := 0; i < trials; i++ {
+			for j, xj := range UniformSimplex(src, dim) {
+				sums[j] += xj
+				sumSquares[j] += xj * xj
+			}
+		}
+		d := float64(dim)
+		expectedVariance := (d - 1) / (d * d * (d + 1))
+		for j := range sums {
+			mean := sums[j] / trials
+			variance := sumSquares[j]/trials - mean*mean
+			require.InDelta(t, 1/d, mean, 5*math.Sqrt(expectedVariance/trials), "dim=%d j=%d", dim, j)
+			require.InDelta(t, expectedVariance, variance, 0.05*expectedVariance, "dim=%d j=%d", dim, j)
+		}
+	}
+}
+
+// TestUniformSimplexOne checks that UniformSimplex() returns {1.0} without using any randomness for dim == 1.
+func TestUniformSimplexOne(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, []float64{1}, UniformSimplex(&src, 1))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestUniformSimplexInvalid checks that UniformSimplex() panics for non-positive dim.
+func TestUniformSimplexInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "dim must be positive in call to UniformSimplex", func() {
+		UniformSimplex(&src, 0)
+	})
+}
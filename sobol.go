@@ -0,0 +1,163 @@
+package random
+
+import "math/bits"
+
+// sobolMaxDim is the largest number of dimensions that Sobol supports.
+const sobolMaxDim = 32
+
+// sobolBits is the number of bits of each coordinate, so a Sobol sequence has 2^sobolBits points.
+const sobolBits = 32
+
+// sobolParams holds the degree s, the coefficients a, and the initial direction numbers m of the primitive
+// polynomial for each dimension after the first, from Joe and Kuo's new-joe-kuo-6.21201 file (see
+// https://web.maths.unsw.edu.au/~fkuo/sobol/ ). The first dimension uses the van der Corput sequence in base 2.
+var sobolParams = [sobolMaxDim - 1]struct {
+	s, a uint32
+	m    []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+	{7, 7, []uint32{1, 1, 3, 13, 7, 35, 63}},
+	{7, 8, []uint32{1, 3, 5, 9, 1, 25, 53}},
+	{7, 14, []uint32{1, 3, 1, 13, 9, 35, 107}},
+	{7, 19, []uint32{1, 3, 1, 5, 27, 61, 31}},
+	{7, 21, []uint32{1, 1, 5, 11, 19, 41, 61}},
+	{7, 28, []uint32{1, 3, 5, 3, 3, 13, 69}},
+	{7, 31, []uint32{1, 1, 7, 13, 1, 19, 1}},
+	{7, 32, []uint32{1, 3, 7, 5, 13, 19, 59}},
+	{7, 37, []uint32{1, 1, 3, 9, 25, 29, 41}},
+	{7, 41, []uint32{1, 3, 5, 13, 23, 1, 55}},
+	{7, 42, []uint32{1, 3, 7, 3, 13, 59, 17}},
+}
+
+// A Sobol generates the Sobol low-discrepancy sequence, for quasi-Monte Carlo methods, optionally with
+// random scrambling. Like Halton, an unscrambled Sobol is completely deterministic, but its points are
+// spread out more evenly, especially in higher dimensions; in particular, for any k, each block of 2^k
+// consecutive points starting from a multiple of 2^k hits each interval [j/2^k, (j+1)/2^k) in each
+// dimension exactly once.
+//
+// Points are generated in Gray code order (as in Joe and Kuo's implementation and most others), starting from
+// the point at the origin, with 32 bits of precision per coordinate, so the sequence has 2³² points.
+//
+// A Sobol is not safe for concurrent use by multiple goroutines.
+type Sobol struct {
+	// v[j][k] is the kth direction number for dimension j, with the first binary digit in the top bit.
+	v [][sobolBits]uint32
+	// x[j] is the current point's coordinate for dimension j, scaled by 2³².
+	x []uint32
+	// i is the index of the next point.
+	i uint64
+}
+
+// NewSobol returns a new unscrambled Sobol for points with dim coordinates, using the direction numbers from
+// Joe and Kuo. dim must be in the range 1 to 32 (inclusive).
+func NewSobol(dim int) *Sobol {
+	if dim < 1 || dim > sobolMaxDim {
+		panic("dim must be in [1, 32] in call to NewSobol")
+	}
+
+	s := &Sobol{
+		v: make([][sobolBits]uint32, dim),
+		x: make([]uint32, dim),
+	}
+	for k := 0; k < sobolBits; k++ {
+		s.v[0][k] = 1 << (sobolBits - 1 - k)
+	}
+	for j := 1; j < dim; j++ {
+		p := sobolParams[j-1]
+		v := &s.v[j]
+		deg := int(p.s)
+		for k := 0; k < deg; k++ {
+			v[k] = p.m[k] << (sobolBits - 1 - k)
+		}
+		for k := deg; k < sobolBits; k++ {
+			v[k] = v[k-deg] ^ (v[k-deg] >> p.s)
+			for l := 1; l < deg; l++ {
+				if (p.a>>(p.s-1-uint32(l)))&1 != 0 {
+					v[k] ^= v[k-l]
+				}
+			}
+		}
+	}
+	return s
+}
+
+// NewScrambledSobol returns a new Sobol for points with dim coordinates, like NewSobol(), but with a random
+// linear matrix scramble and a random digital shift drawn from src, as in Matoušek's "On the L2-discrepancy
+// for anchored boxes" (1998). Each point is then uniformly distributed in [0, 1)^dim, so independently
+// scrambled sequences can be used as randomized quasi-Monte Carlo replicates, but the stratification
+// properties described for Sobol still hold. dim must be in the range 1 to 32 (inclusive).
+func NewScrambledSobol(dim int, src Source) *Sobol {
+	if dim < 1 || dim > sobolMaxDim {
+		panic("dim must be in [1, 32] in call to NewScrambledSobol")
+	}
+
+	s := NewSobol(dim)
+	for j := range s.v {
+		// Row r of the lower-triangular scrambling matrix maps the first r+1 binary digits of a direction
+		// number to its new rth digit, i.e. the bit for digit r is set, the bits for the earlier (higher)
+		// digits are random, and the bits for the later (lower) digits are clear.
+		var rows [sobolBits]uint32
+		for r := range rows {
+			bit := uint32(1) << (sobolBits - 1 - r)
+			rows[r] = bit | (randUint32(src) &^ (bit<<1 - 1))
+		}
+		for k, v := range s.v[j] {
+			var scrambled uint32
+			for r, row := range rows {
+				scrambled |= uint32(bits.OnesCount32(row&v)&1) << (sobolBits - 1 - r)
+			}
+			s.v[j][k] = scrambled
+		}
+		// Start from a random digital shift instead of the origin.
+		s.x[j] = randUint32(src)
+	}
+	return s
+}
+
+// Dim returns the number of coordinates of each point.
+func (s *Sobol) Dim() int {
+	return len(s.x)
+}
+
+// Next fills in out with the next point of the sequence, which is in [0, 1)^Dim(). len(out) must equal
+// s.Dim(). It panics if all 2³² points have already been returned.
+func (s *Sobol) Next(out []float64) {
+	if len(out) != len(s.x) {
+		panic("len(out) must equal Dim() in call to Sobol.Next")
+	}
+	if s.i >= 1<<sobolBits {
+		panic("Sobol sequence exhausted")
+	}
+
+	// The 0th point is the initial value of x. After that, the ith point differs from the previous one in
+	// the direction numbers for the lowest set bit of i, since that's the bit that changes in the Gray code.
+	if s.i > 0 {
+		c := bits.TrailingZeros64(s.i)
+		for j := range s.x {
+			s.x[j] ^= s.v[j][c]
+		}
+	}
+	s.i++
+	for j, x := range s.x {
+		out[j] = float64(x) / (1 << sobolBits)
+	}
+}
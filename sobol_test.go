@@ -0,0 +1,152 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSobolReference checks the first few points of the five-dimensional unscrambled Sobol sequence against
+// the reference values (e.g., from SciPy's scipy.stats.qmc.Sobol with scramble=False, which also uses Joe
+// and Kuo's direction numbers).
+func TestSobolReference(t *testing.T) {
+	t.Parallel()
+	expected := [][]float64{
+		{0, 0, 0, 0, 0},
+		{0.5, 0.5, 0.5, 0.5, 0.5},
+		{0.75, 0.25, 0.25, 0.25, 0.75},
+		{0.25, 0.75, 0.75, 0.75, 0.25},
+		{0.375, 0.375, 0.625, 0.875, 0.375},
+		{0.875, 0.875, 0.125, 0.375, 0.875},
+		{0.625, 0.125, 0.875, 0.625, 0.625},
+		{0.125, 0.625, 0.375, 0.125, 0.125},
+	}
+	s := NewSobol(5)
+	out := make([]float64, 5)
+	for i, point := range expected {
+		s.Next(out)
+		require.Equal(t, point, out, "i=%d", i)
+	}
+}
+
+// requireSobolStratified checks that each of the next 2^k points of s lands in a different interval
+// [m/2^k, (m+1)/2^k) in each dimension.
+func requireSobolStratified(t *testing.T, s *Sobol, k uint) {
+	n := 1 << k
+	seen := make([][]bool, s.Dim())
+	for j := range seen {
+		seen[j] = make([]bool, n)
+	}
+	out := make([]float64, s.Dim())
+	for i := 0; i < n; i++ {
+		s.Next(out)
+		for j, x := range out {
+			require.True(t, x >= 0 && x < 1, "i=%d j=%d x=%v", i, j, x)
+			m := int(x * float64(n))
+			require.False(t, seen[j][m], "i=%d j=%d m=%d", i, j, m)
+			seen[j][m] = true
+		}
+	}
+}
+
+// TestSobolStratified checks that the first 2⁸ points of the unscrambled and scrambled Sobol sequences are
+// stratified in each of the 32 dimensions.
+func TestSobolStratified(t *testing.T) {
+	t.Parallel()
+	requireSobolStratified(t, NewSobol(32), 8)
+	requireSobolStratified(t, NewScrambledSobol(32, rand.NewSource(1)), 8)
+}
+
+// l2StarDiscrepancy returns the L2-star discrepancy of the given points in [0, 1)^d, using Warnock's
+// formula.
+func l2StarDiscrepancy(points [][]float64) float64 {
+	n := float64(len(points))
+	d := len(points[0])
+	var sum1, sum2 float64
+	for _, p := range points {
+		prod := 1.0
+		for _, x := range p {
+			prod *= 1 - x*x
+		}
+		sum1 += prod
+		for _, q := range points {
+			prod := 1.0
+			for k := range p {
+				prod *= 1 - math.Max(p[k], q[k])
+			}
+			sum2 += prod
+		}
+	}
+	return math.Sqrt(math.Pow(3, -float64(d)) - math.Pow(2, 1-float64(d))/n*sum1 + sum2/(n*n))
+}
+
+// TestSobolDiscrepancy checks that the unscrambled and scrambled Sobol sequences have a much lower L2-star
+// discrepancy than pseudo-random points.
+func TestSobolDiscrepancy(t *testing.T) {
+	t.Parallel()
+	const n = 1024
+	const dim = 5
+	generate := func(next func(out []float64)) [][]float64 {
+		points := make([][]float64, n)
+		for i := range points {
+			points[i] = make([]float64, dim)
+			next(points[i])
+		}
+		return points
+	}
+
+	src := rand.NewSource(1)
+	random := l2StarDiscrepancy(generate(func(out []float64) {
+		for j := range out {
+			out[j] = Float64(src)
+		}
+	}))
+	sobol := l2StarDiscrepancy(generate(NewSobol(dim).Next))
+	scrambled := l2StarDiscrepancy(generate(NewScrambledSobol(dim, src).Next))
+	require.Less(t, 4*sobol, random, "sobol=%v random=%v", sobol, random)
+	require.Less(t, 4*scrambled, random, "scrambled=%v random=%v", scrambled, random)
+}
+
+// TestScrambledSobolDeterministic checks that NewScrambledSobol() returns the same sequence for the same
+// seed, and different sequences for different seeds.
+func TestScrambledSobolDeterministic(t *testing.T) {
+	t.Parallel()
+	s1 := NewScrambledSobol(3, rand.NewSource(1))
+	s2 := NewScrambledSobol(3, rand.NewSource(1))
+	s3 := NewScrambledSobol(3, rand.NewSource(2))
+	out1 := make([]float64, 3)
+	out2 := make([]float64, 3)
+	out3 := make([]float64, 3)
+	differences := 0
+	for i := 0; i < 100; i++ {
+		s1.Next(out1)
+		s2.Next(out2)
+		s3.Next(out3)
+		require.Equal(t, out1, out2, "i=%d", i)
+		for j := range out1 {
+			if out1[j] != out3[j] {
+				differences++
+			}
+		}
+	}
+	require.Equal(t, 300, differences)
+}
+
+// TestSobolInvalid checks that NewSobol(), NewScrambledSobol(), and Sobol.Next() panic for invalid
+// arguments.
+func TestSobolInvalid(t *testing.T) {
+	t.Parallel()
+	for _, dim := range []int{0, 33} {
+		require.PanicsWithValue(t, "dim must be in [1, 32] in call to NewSobol", func() {
+			NewSobol(dim)
+		}, "dim=%d", dim)
+		require.PanicsWithValue(t, "dim must be in [1, 32] in call to NewScrambledSobol", func() {
+			NewScrambledSobol(dim, rand.NewSource(1))
+		}, "dim=%d", dim)
+	}
+	require.PanicsWithValue(t, "len(out) must equal Dim() in call to Sobol.Next", func() {
+		NewSobol(2).Next(make([]float64, 3))
+	})
+}
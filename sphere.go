@@ -0,0 +1,49 @@
+package random
+
+import "math"
+
+// UnitVector3 returns a pseudo-random point uniformly distributed on the unit sphere in three dimensions.
+//
+// This uses Marsaglia's method from "Choosing a Point from the Surface of a Sphere" (1972), which picks a
+// uniformly-distributed point (u, v) in the unit disk by rejection, and then maps it to the sphere with
+// s = u² + v²: (2u*sqrt(1-s), 2v*sqrt(1-s), 1-2s). Since s is uniform on [0, 1), z = 1-2s is uniform on
+// (-1, 1], as required by Archimedes' hat-box theorem.
+func UnitVector3(src Source) (x, y, z float64) {
+	for {
+		u := 2*Float64(src) - 1
+		v := 2*Float64(src) - 1
+		s := u*u + v*v
+		if s < 1 {
+			r := 2 * math.Sqrt(1-s)
+			return u * r, v * r, 1 - 2*s
+		}
+	}
+}
+
+// UnitVectorN fills in out with a pseudo-random point uniformly distributed on the unit sphere in len(out)
+// dimensions. out must be non-empty. For len(out) == 1, the result is -1 or 1 with equal probability.
+//
+// This uses the standard method of drawing len(out) independent values from NormFloat64() and dividing each
+// by their Euclidean norm, which works since the multivariate normal distribution is spherically symmetric.
+func UnitVectorN(src Source, out []float64) {
+	if len(out) == 0 {
+		panic("out must be non-empty in call to UnitVectorN")
+	}
+
+	for {
+		var sumSquares float64
+		for i := range out {
+			out[i] = NormFloat64(src)
+			sumSquares += out[i] * out[i]
+		}
+		// A zero vector can't be normalized, although it's vanishingly unlikely.
+		if sumSquares == 0 {
+			continue
+		}
+		norm := math.Sqrt(sumSquares)
+		for i := range out {
+			out[i] /= norm
+		}
+		return
+	}
+}
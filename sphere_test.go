@@ -0,0 +1,94 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnitVector3 checks that UnitVector3() returns unit vectors whose z-components are uniform on [-1, 1].
+func TestUnitVector3(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const buckets = 20
+	src := rand.NewSource(1)
+	var counts [buckets]int
+	for i := 0; i < trials; i++ {
+		x, y, z := UnitVector3(src)
+		require.InDelta(t, 1, x*x+y*y+z*z, 1e-12)
+		b := int((z + 1) / 2 * buckets)
+		if b == buckets {
+			b--
+		}
+		counts[b]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1.0/buckets, count, "i=%d", i)
+	}
+}
+
+// TestUnitVector3Octants checks that UnitVector3() returns points in each octant equally often.
+func TestUnitVector3Octants(t *testing.T) {
+	t.Parallel()
+	const trials = 80000
+	src := rand.NewSource(2)
+	var counts [8]int
+	for i := 0; i < trials; i++ {
+		x, y, z := UnitVector3(src)
+		octant := 0
+		if x > 0 {
+			octant |= 1
+		}
+		if y > 0 {
+			octant |= 2
+		}
+		if z > 0 {
+			octant |= 4
+		}
+		counts[octant]++
+	}
+	for i, count := range counts {
+		requireBinomialCount(t, trials, 1.0/8, count, "i=%d", i)
+	}
+}
+
+// TestUnitVectorN checks that UnitVectorN() returns unit vectors, and that each component has mean 0 and
+// variance 1/dim.
+func TestUnitVectorN(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	for _, dim := range []int{1, 2, 3, 10} {
+		src := rand.NewSource(int64(dim))
+		out := make([]float64, dim)
+		sums := make([]float64, dim)
+		sumSquares := make([]float64, dim)
+		for i := 0; i < trials; i++ {
+			UnitVectorN(src, out)
+			var norm2 float64
+			for j, v := range out {
+				norm2 += v * v
+				sums[j] += v
+				sumSquares[j] += v * v
+			}
+			require.InDelta(t, 1, norm2, 1e-12, "dim=%d", dim)
+		}
+		for j := 0; j < dim; j++ {
+			// Each component is bounded by 1, so its variance is at most 1/dim, and the variance of its
+			// square is at most 1/dim too.
+			require.InDelta(t, 0, sums[j]/trials, 5*math.Sqrt(1/float64(dim)/trials), "dim=%d j=%d", dim, j)
+			require.InDelta(t, 1/float64(dim), sumSquares[j]/trials, 5*math.Sqrt(1/float64(dim)/trials),
+				"dim=%d j=%d", dim, j)
+		}
+	}
+}
+
+// TestUnitVectorNEmpty checks that UnitVectorN() panics for an empty out.
+func TestUnitVectorNEmpty(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "out must be non-empty in call to UnitVectorN", func() {
+		UnitVectorN(&src, nil)
+	})
+}
@@ -0,0 +1,156 @@
+package random
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// A SplitMix64 is a Source64 that implements Steele, Lea, and Flood's SplitMix64 generator, as given at
+// https://prng.di.unimi.it/splitmix64.c . Like PCG, its output is completely specified, so it's identical
+// across platforms and Go versions. It has a period of 2⁶⁴, and its state is a single uint64, so it's cheap to
+// create, but its statistical quality isn't as good as that of Xoshiro256, which is seeded with it.
+//
+// Each call to Uint64() returns one SplitMix64 output, and Int63() returns its top 63 bits.
+//
+// A SplitMix64 is not safe for concurrent use by multiple goroutines.
+type SplitMix64 struct {
+	state uint64
+}
+
+// NewSplitMix64 returns a new SplitMix64 with the given initial state.
+func NewSplitMix64(seed uint64) *SplitMix64 {
+	return &SplitMix64{seed}
+}
+
+// Reseed resets s to the given state, so that it returns the same values as a new SplitMix64 returned by
+// NewSplitMix64(seed) would.
+func (s *SplitMix64) Reseed(seed uint64) {
+	s.state = seed
+}
+
+// Uint64 returns the next SplitMix64 output, which is uniformly distributed in the range 0 to 2⁶⁴-1
+// (inclusive).
+func (s *SplitMix64) Uint64() uint64 {
+	return splitMix64(&s.state)
+}
+
+// Int63 returns the top 63 bits of s.Uint64().
+func (s *SplitMix64) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Clone returns a new SplitMix64 with the same state as s.
+func (s *SplitMix64) Clone() Source {
+	clone := *s
+	return &clone
+}
+
+// splitMix64 advances the SplitMix64 state *state and returns the next output. This is used to seed other
+// generators from a single uint64, as recommended at https://prng.di.unimi.it/ .
+func splitMix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// SeedFromString returns a new SplitMix64 seeded with the 64-bit FNV-1a hash of the bytes of s. This is
+// useful for letting users pass human-readable seeds, e.g. from a command-line flag. Since both the hash and
+// the generator are completely specified, the same string always gives the same stream, across runs,
+// platforms, and Go versions.
+func SeedFromString(s string) Source {
+	h := fnv.New64a()
+	// Writes to a hash.Hash never return an error.
+	_, _ = h.Write([]byte(s))
+	return NewSplitMix64(h.Sum64())
+}
+
+// autoSeedCount is the number of calls to AutoSeed() so far, which is mixed into each seed so that calls in the
+// same process never get the same seed from the other inputs alone.
+var autoSeedCount uint64
+
+// AutoSeed returns a new SplitMix64 seeded with a mix of the current time, 8 bytes from crypto/rand, and a
+// per-process counter, so that each call (in the same process or in different ones) gets a distinct stream
+// without the caller having to pick a seed. Unlike seeding with the time alone, this doesn't give the same
+// stream to processes that start at the same time. If crypto/rand fails, which it doesn't on any supported
+// platform in recent Go versions, the seed is mixed from the time and counter alone.
+//
+// The seed isn't recoverable from the returned Source, so use NewSplitMix64() or SeedFromString() instead if
+// the stream needs to be reproducible.
+func AutoSeed() Source {
+	var buf [8]byte
+	_, _ = crand.Read(buf[:])
+	count := atomic.AddUint64(&autoSeedCount, 1)
+	seed := uint64(time.Now().UnixNano()) ^ binary.LittleEndian.Uint64(buf[:]) ^ splitMix64(&count)
+	return NewSplitMix64(seed)
+}
+
+// GenerateVector returns count values of Uint32n(src, n), where src is a new SplitMix64 seeded with seed. n
+// must be non-zero, and count must be non-negative.
+//
+// Since SplitMix64 and Uint32n() are completely specified, the output only depends on the arguments, and is
+// stable across platforms and Go versions, unlike the output of the sources returned by rand.NewSource(). This
+// is the recommended way to produce reproducible test fixtures, e.g. for golden files.
+func GenerateVector(seed uint64, n uint32, count int) []uint32 {
+	if n == 0 {
+		panic("n must be non-zero in call to GenerateVector")
+	}
+	if count < 0 {
+		panic("count must be non-negative in call to GenerateVector")
+	}
+
+	src := NewSplitMix64(seed)
+	vs := make([]uint32, count)
+	for i := range vs {
+		vs[i] = Uint32n(src, n)
+	}
+	return vs
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the state of s compactly, so that it can be
+// saved and restored later, e.g. to resume a long-running simulation. The encoding starts with a version byte,
+// followed by the state as a big-endian uint64.
+func (s *SplitMix64) MarshalBinary() ([]byte, error) {
+	return marshalState(s.state), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring the state saved by MarshalBinary(), so that
+// s returns the same values that the encoded SplitMix64 would have.
+func (s *SplitMix64) UnmarshalBinary(data []byte) error {
+	return unmarshalState(data, &s.state)
+}
+
+// GobEncode implements gob.GobEncoder, using the same encoding as MarshalBinary().
+func (s *SplitMix64) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using the same encoding as UnmarshalBinary().
+func (s *SplitMix64) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// splitMix64JSON is the JSON encoding of the state of a SplitMix64.
+type splitMix64JSON struct {
+	State uint64 `json:"state"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the state of s as a JSON object.
+func (s *SplitMix64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(splitMix64JSON{s.state})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring the state saved by MarshalJSON().
+func (s *SplitMix64) UnmarshalJSON(data []byte) error {
+	var j splitMix64JSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	s.state = j.State
+	return nil
+}
@@ -0,0 +1,120 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitMix64Reference checks splitMix64() against the reference outputs for seed 1234567.
+func TestSplitMix64Reference(t *testing.T) {
+	t.Parallel()
+	state := uint64(1234567)
+	var vs []uint64
+	for i := 0; i < 5; i++ {
+		vs = append(vs, splitMix64(&state))
+	}
+	require.Equal(t, []uint64{
+		6457827717110365317, 3203168211198807973, 9817491932198370423,
+		4593380528125082431, 16408922859458223821,
+	}, vs)
+}
+
+// TestSplitMix64 checks that SplitMix64 returns the reference outputs for seed 1234567, and that Int63()
+// returns the top 63 bits of Uint64().
+func TestSplitMix64(t *testing.T) {
+	t.Parallel()
+	s := NewSplitMix64(1234567)
+	require.Equal(t, uint64(6457827717110365317), s.Uint64())
+	require.Equal(t, int64(3203168211198807973>>1), s.Int63())
+}
+
+// TestSeedFromString checks that SeedFromString() seeds a SplitMix64 with the FNV-1a hash of the string.
+func TestSeedFromString(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, NewSplitMix64(0xcbf29ce484222325), SeedFromString(""))
+	require.Equal(t, NewSplitMix64(0xa430d84680aabd0b), SeedFromString("hello"))
+}
+
+// TestSeedFromStringDeterministic checks that the same string gives the same Uint32n() values, and that
+// different strings diverge right away.
+func TestSeedFromStringDeterministic(t *testing.T) {
+	t.Parallel()
+	const n = 1000
+	draw := func(s string) []uint32 {
+		src := SeedFromString(s)
+		vs := make([]uint32, 100)
+		for i := range vs {
+			vs[i] = Uint32n(src, n)
+		}
+		return vs
+	}
+
+	hello := draw("hello")
+	require.Equal(t, hello, draw("hello"))
+	for _, s := range []string{"", "Hello", "hello ", "hellp", "world"} {
+		other := draw(s)
+		matches := 0
+		for i := range hello {
+			if hello[i] == other[i] {
+				matches++
+			}
+		}
+		// Each value matches with probability 1/n.
+		require.LessOrEqual(t, matches, 3, "s=%q", s)
+	}
+}
+
+// TestAutoSeedDistinct checks that calls to AutoSeed() in quick succession return Sources with different
+// first outputs. This fails with probability about 2⁻⁶³ per pair.
+func TestAutoSeedDistinct(t *testing.T) {
+	t.Parallel()
+	seen := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		v := AutoSeed().Int63()
+		require.False(t, seen[v], "i=%d", i)
+		seen[v] = true
+	}
+}
+
+// TestAutoSeedUniform checks that the Source returned by AutoSeed() is a working Source.
+func TestAutoSeedUniform(t *testing.T) {
+	t.Parallel()
+	src := AutoSeed()
+	_, ok := src.(Source64)
+	require.True(t, ok)
+	// Since the seed is random, use a lower threshold than the default to keep this from being flaky.
+	require.NoError(t, TestSourceUniformity(src, 100, 100000, WithUniformityThreshold(1e-9)))
+}
+
+// TestGenerateVectorGolden checks GenerateVector() against fixed values (computed independently from the
+// SplitMix64 reference code and the definition of Uint32n()), so that any change to its output is caught.
+func TestGenerateVectorGolden(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, []uint32{74, 15, 27, 34, 3, 86, 21, 80, 33, 61}, GenerateVector(42, 100, 10))
+	require.Equal(t, []uint32{2084953172, 1656883613, 2044470342, 851408494, 1634308976},
+		GenerateVector(0, 1<<31+1, 5))
+	require.Equal(t, []uint32{}, GenerateVector(42, 100, 0))
+}
+
+// TestGenerateVectorPure checks that GenerateVector() returns the same values for the same arguments, and
+// that a longer vector extends a shorter one.
+func TestGenerateVectorPure(t *testing.T) {
+	t.Parallel()
+	for _, seed := range []uint64{0, 1, 1<<64 - 1} {
+		v := GenerateVector(seed, 1000, 100)
+		require.Equal(t, v, GenerateVector(seed, 1000, 100), "seed=%d", seed)
+		require.Equal(t, v[:50], GenerateVector(seed, 1000, 50), "seed=%d", seed)
+	}
+}
+
+// TestGenerateVectorInvalid checks that GenerateVector() panics for n == 0 or negative count.
+func TestGenerateVectorInvalid(t *testing.T) {
+	t.Parallel()
+	require.PanicsWithValue(t, "n must be non-zero in call to GenerateVector", func() {
+		GenerateVector(1, 0, 1)
+	})
+	require.PanicsWithValue(t, "count must be non-negative in call to GenerateVector", func() {
+		GenerateVector(1, 1, -1)
+	})
+}
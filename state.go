@@ -0,0 +1,53 @@
+package random
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidState is wrapped by the errors returned when decoding the state of a SplitMix64, PCG, or Xoshiro256
+// fails, e.g. because the encoded state has the wrong length or isn't a valid state for that generator.
+var ErrInvalidState = errors.New("invalid source state")
+
+// stateVersion is the version of the binary encoding of source states, which is the first byte of the encoding.
+// If the encoding of a source's state ever changes, this will be incremented, and UnmarshalBinary() will
+// continue to accept the older versions.
+const stateVersion = 1
+
+// marshalState returns the binary encoding of a source state made up of words: the version byte followed by
+// the big-endian encoding of each word.
+func marshalState(words ...uint64) []byte {
+	return append([]byte{stateVersion}, encodeWords(words...)...)
+}
+
+// unmarshalState decodes data, which must be the output of marshalState() for len(words) words, into words.
+func unmarshalState(data []byte, words ...*uint64) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty encoding", ErrInvalidState)
+	}
+	if data[0] != stateVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidState, data[0])
+	}
+	return decodeWords(data[1:], words...)
+}
+
+// encodeWords returns the big-endian encoding of words, 8 bytes per word.
+func encodeWords(words ...uint64) []byte {
+	data := make([]byte, 8*len(words))
+	for i, w := range words {
+		binary.BigEndian.PutUint64(data[8*i:], w)
+	}
+	return data
+}
+
+// decodeWords decodes data, which must be the output of encodeWords() for len(words) words, into words.
+func decodeWords(data []byte, words ...*uint64) error {
+	if len(data) != 8*len(words) {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidState, len(data), 8*len(words))
+	}
+	for i, w := range words {
+		*w = binary.BigEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package random
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stateTestCase is a source whose state can be saved and restored, along with a function returning a new
+// zero value of its type to restore into.
+type stateTestCase struct {
+	name    string
+	src     Source64
+	newZero func() Source64
+}
+
+// stateTestCases returns a test case for each source type with a saveable state.
+func stateTestCases() []stateTestCase {
+	return []stateTestCase{
+		{"SplitMix64", NewSplitMix64(1), func() Source64 { return &SplitMix64{} }},
+		{"PCG", NewPCG(42, 54), func() Source64 { return &PCG{} }},
+		{"Xoshiro256", NewXoshiro256(1), func() Source64 { return &Xoshiro256{} }},
+	}
+}
+
+// requireSameContinuation checks that restored returns the same values as src from now on.
+func requireSameContinuation(t *testing.T, src, restored Source64, name string) {
+	for i := 0; i < 100; i++ {
+		require.Equal(t, src.Uint64(), restored.Uint64(), "%s i=%d", name, i)
+	}
+}
+
+// TestGobRoundTrip checks that each source returns the same continuation after a round trip through gob.
+func TestGobRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tc := range stateTestCases() {
+		for i := 0; i < 10; i++ {
+			tc.src.Uint64()
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(tc.src), tc.name)
+		restored := tc.newZero()
+		require.NoError(t, gob.NewDecoder(&buf).Decode(restored), tc.name)
+		requireSameContinuation(t, tc.src, restored, tc.name)
+	}
+}
+
+// TestJSONRoundTrip checks that each source returns the same continuation after a round trip through JSON.
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tc := range stateTestCases() {
+		for i := 0; i < 10; i++ {
+			tc.src.Uint64()
+		}
+
+		data, err := json.Marshal(tc.src)
+		require.NoError(t, err, tc.name)
+		restored := tc.newZero()
+		require.NoError(t, json.Unmarshal(data, restored), tc.name)
+		requireSameContinuation(t, tc.src, restored, tc.name)
+	}
+}
+
+// TestJSONFormat checks the JSON encoding of each source.
+func TestJSONFormat(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(&SplitMix64{state: 1<<64 - 1})
+	require.NoError(t, err)
+	require.Equal(t, `{"state":18446744073709551615}`, string(data))
+
+	data, err = json.Marshal(&PCG{state: 1, inc: 3})
+	require.NoError(t, err)
+	require.Equal(t, `{"state":1,"inc":3}`, string(data))
+
+	data, err = json.Marshal(&Xoshiro256{s: [4]uint64{1, 2, 3, 4}})
+	require.NoError(t, err)
+	require.Equal(t, `{"s":[1,2,3,4]}`, string(data))
+}
+
+// TestBinaryRoundTrip checks that each source returns the same continuation after a round trip through
+// MarshalBinary() and UnmarshalBinary().
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tc := range stateTestCases() {
+		for i := 0; i < 10; i++ {
+			tc.src.Uint64()
+		}
+
+		data, err := tc.src.(encoding.BinaryMarshaler).MarshalBinary()
+		require.NoError(t, err, tc.name)
+		restored := tc.newZero()
+		require.NoError(t, restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(data), tc.name)
+		requireSameContinuation(t, tc.src, restored, tc.name)
+	}
+}
+
+// TestBinaryFormat checks the binary encoding of each source.
+func TestBinaryFormat(t *testing.T) {
+	t.Parallel()
+	data, err := (&SplitMix64{state: 0x0102030405060708}).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 1, 2, 3, 4, 5, 6, 7, 8}, data)
+
+	data, err = (&PCG{state: 1, inc: 3}).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 3}, data)
+
+	data, err = (&Xoshiro256{s: [4]uint64{1, 2, 3, 4}}).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, 33, len(data))
+	require.Equal(t, byte(1), data[0])
+	require.Equal(t, encodeWords(1, 2, 3, 4), data[1:])
+}
+
+// TestDecodeInvalidState checks that decoding a state that is truncated, has the wrong length or version, or
+// isn't valid for the generator returns an error wrapping ErrInvalidState, and leaves the source unchanged.
+func TestDecodeInvalidState(t *testing.T) {
+	t.Parallel()
+	for _, tc := range stateTestCases() {
+		data, err := tc.src.(encoding.BinaryMarshaler).MarshalBinary()
+		require.NoError(t, err, tc.name)
+		original := tc.src.(Cloneable).Clone().(Source64)
+		u := tc.src.(encoding.BinaryUnmarshaler)
+
+		wrongVersion := append([]byte{2}, data[1:]...)
+		for _, bad := range [][]byte{nil, data[:1], data[:len(data)-1], append(data, 0), wrongVersion} {
+			err = u.UnmarshalBinary(bad)
+			require.True(t, errors.Is(err, ErrInvalidState), "%s bad=%v err=%v", tc.name, bad, err)
+			err = tc.src.(gob.GobDecoder).GobDecode(bad)
+			require.True(t, errors.Is(err, ErrInvalidState), "%s bad=%v err=%v", tc.name, bad, err)
+		}
+		requireSameContinuation(t, original, tc.src, tc.name)
+	}
+
+	p := NewPCG(1, 2)
+	expected := *p
+	err := p.UnmarshalBinary(marshalState(1, 2))
+	require.True(t, errors.Is(err, ErrInvalidState), "err=%v", err)
+	err = json.Unmarshal([]byte(`{"state":1,"inc":2}`), p)
+	require.True(t, errors.Is(err, ErrInvalidState), "err=%v", err)
+	require.Equal(t, expected, *p)
+
+	x := NewXoshiro256(1)
+	expectedX := *x
+	err = x.UnmarshalBinary(marshalState(0, 0, 0, 0))
+	require.True(t, errors.Is(err, ErrInvalidState), "err=%v", err)
+	err = json.Unmarshal([]byte(`{"s":[0,0,0,0]}`), x)
+	require.True(t, errors.Is(err, ErrInvalidState), "err=%v", err)
+	require.Equal(t, expectedX, *x)
+}
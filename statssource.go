@@ -0,0 +1,33 @@
+package random
+
+// A StatsSource wraps a Source and counts the number of calls to Int63(). This is useful for measuring how
+// often functions like Uint32n() reject values: for example, the number of calls after k calls to
+// Uint32n(statsSrc, n), divided by k, is the average number of draws per value.
+//
+// A StatsSource only implements Source, even if the wrapped Source also implements Source64, so that every
+// draw goes through Int63().
+type StatsSource struct {
+	src   Source
+	calls int64
+}
+
+// NewStatsSource returns a new StatsSource wrapping src.
+func NewStatsSource(src Source) *StatsSource {
+	return &StatsSource{src: src}
+}
+
+// Int63 returns the result of Int63() on the wrapped Source, and increments the call count.
+func (s *StatsSource) Int63() int64 {
+	s.calls++
+	return s.src.Int63()
+}
+
+// Calls returns the number of calls to Int63() since s was created or last reset.
+func (s *StatsSource) Calls() int64 {
+	return s.calls
+}
+
+// Reset sets the call count back to zero.
+func (s *StatsSource) Reset() {
+	s.calls = 0
+}
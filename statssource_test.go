@@ -0,0 +1,51 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatsSourceCalls checks that StatsSource passes through the values of the wrapped Source, and counts
+// each call to Int63() exactly once.
+func TestStatsSourceCalls(t *testing.T) {
+	t.Parallel()
+	src := NewStatsSource(rand.NewSource(1))
+	expectedSrc := rand.NewSource(1)
+	for i := int64(0); i < 100; i++ {
+		require.Equal(t, i, src.Calls())
+		require.Equal(t, expectedSrc.Int63(), src.Int63())
+	}
+	require.Equal(t, int64(100), src.Calls())
+
+	src.Reset()
+	require.Equal(t, int64(0), src.Calls())
+	src.Int63()
+	require.Equal(t, int64(1), src.Calls())
+}
+
+// TestStatsSourceUint32nPowerOfTwo checks that Uint32n() makes exactly one call to Int63() for a power of two
+// n, as counted by a StatsSource.
+func TestStatsSourceUint32nPowerOfTwo(t *testing.T) {
+	t.Parallel()
+	src := NewStatsSource(rand.NewSource(2))
+	for i := uint32(0); i < 32; i++ {
+		for j := 0; j < 100; j++ {
+			Uint32n(src, 1<<i)
+		}
+	}
+	require.Equal(t, int64(32*100), src.Calls())
+}
+
+// TestStatsSourceUint32nRejection checks that StatsSource counts the extra draws when Uint32n() rejects
+// values.
+func TestStatsSourceUint32nRejection(t *testing.T) {
+	t.Parallel()
+	for r := 0; r < 3; r++ {
+		testSrc := makeTestSource(r, 1)
+		src := NewStatsSource(&testSrc)
+		Uint32n(src, 3)
+		require.Equal(t, int64(r+1), src.Calls())
+	}
+}
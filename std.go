@@ -0,0 +1,52 @@
+package random
+
+import "math/rand"
+
+// AsStdSource returns a rand.Source64 that draws from src, so that src can be used with rand.New() and other
+// code that expects a rand.Source64. If src already implements rand.Source64, it's returned as is.
+//
+// Otherwise, the returned rand.Source64's Int63() calls src.Int63(), its Uint64() returns randUint64(src)
+// (i.e., src.Uint64() if src is a Source64, or else a value built from two calls to src.Int63()), and its
+// Seed() calls src.Reseed() if src is a Reseedable, and otherwise does nothing.
+func AsStdSource(src Source) rand.Source64 {
+	if stdSrc, ok := src.(rand.Source64); ok {
+		return stdSrc
+	}
+	return stdSource{src}
+}
+
+// A stdSource is the rand.Source64 returned by AsStdSource().
+type stdSource struct {
+	src Source
+}
+
+// Int63 returns s.src.Int63().
+func (s stdSource) Int63() int64 {
+	return s.src.Int63()
+}
+
+// Uint64 returns randUint64(s.src).
+func (s stdSource) Uint64() uint64 {
+	return randUint64(s.src)
+}
+
+// Seed reseeds s.src if it's a Reseedable, and otherwise does nothing.
+func (s stdSource) Seed(seed int64) {
+	if r, ok := s.src.(Reseedable); ok {
+		r.Reseed(uint64(seed))
+	}
+}
+
+// FromStd returns a Source that draws from r, so that r can be used with the functions in this package.
+//
+// Since *rand.Rand already has Int63() and Uint64() methods, it already implements Source64, so this just
+// returns r; it exists so that callers don't have to write their own adapters, which are easy to get subtly
+// wrong, e.g. by only implementing Int63(), which makes the functions that need 64-bit values call it twice.
+// Note that *rand.Rand also has a Uint32() method, which the functions that need 32-bit values use, so they may
+// return different values than they would for the rand.Source that r wraps.
+//
+// Like r itself, the returned Source is not safe for concurrent use by multiple goroutines (unless r is the
+// result of rand.New() on a Source that is).
+func FromStd(r *rand.Rand) Source {
+	return r
+}
@@ -0,0 +1,80 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAsStdSource checks that the rand.Source64 returned by AsStdSource() draws from the wrapped Source, and
+// that it can be used with rand.New().
+func TestAsStdSource(t *testing.T) {
+	t.Parallel()
+	expectedSrc := NewSplitMix64(1)
+	stdSrc := AsStdSource(NewSplitMix64(1))
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expectedSrc.Int63(), stdSrc.Int63(), "i=%d", i)
+		require.Equal(t, expectedSrc.Uint64(), stdSrc.Uint64(), "i=%d", i)
+	}
+
+	r := rand.New(AsStdSource(NewSplitMix64(2)))
+	for i := 0; i < 100; i++ {
+		v := r.Intn(10)
+		require.True(t, v >= 0 && v < 10, "i=%d v=%d", i, v)
+	}
+}
+
+// TestAsStdSourceUint64 checks that the rand.Source64 returned by AsStdSource() builds each Uint64() value
+// from two calls to Int63() if the wrapped Source isn't a Source64.
+func TestAsStdSourceUint64(t *testing.T) {
+	t.Parallel()
+	src := &int63Source{vs: []int64{1 << 62, 0x12345678}}
+	stdSrc := AsStdSource(src)
+	require.Equal(t, randUint64(&int63Source{vs: src.vs}), stdSrc.Uint64())
+	require.Equal(t, 2, src.callCount)
+}
+
+// TestAsStdSourceSeed checks that Seed() on the rand.Source64 returned by AsStdSource() reseeds a Reseedable,
+// and does nothing otherwise.
+func TestAsStdSourceSeed(t *testing.T) {
+	t.Parallel()
+	src := NewPCG(1, 2)
+	stdSrc := AsStdSource(src)
+	stdSrc.Seed(5)
+	expected := NewPCG(1, 2)
+	expected.Reseed(5)
+	require.Equal(t, expected.Int63(), stdSrc.Int63())
+
+	tSrc := makeTestSource(0, 0x12345678)
+	stdSrc = AsStdSource(&tSrc)
+	stdSrc.Seed(5)
+	require.Equal(t, int64(0x12345678)<<31, stdSrc.Int63())
+}
+
+// TestAsStdSourcePassthrough checks that AsStdSource() returns a rand.Source64 as is.
+func TestAsStdSourcePassthrough(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1).(rand.Source64)
+	require.Equal(t, src, AsStdSource(src))
+}
+
+// TestFromStd checks that the Source returned by FromStd() gives values in range for Uint32n(), that it's
+// reproducible for a seeded rand.Rand, and that it's a Source64.
+func TestFromStd(t *testing.T) {
+	t.Parallel()
+	src := FromStd(rand.New(rand.NewSource(1)))
+	expectedSrc := FromStd(rand.New(rand.NewSource(1)))
+	for i := 0; i < 100; i++ {
+		v := Uint32n(src, 10)
+		require.Less(t, v, uint32(10), "i=%d", i)
+		require.Equal(t, Uint32n(expectedSrc, 10), v, "i=%d", i)
+	}
+
+	_, ok := FromStd(rand.New(rand.NewSource(1))).(Source64)
+	require.True(t, ok)
+
+	p1 := Perm(FromStd(rand.New(rand.NewSource(2))), 20)
+	p2 := Perm(FromStd(rand.New(rand.NewSource(2))), 20)
+	require.Equal(t, p1, p2)
+}
@@ -0,0 +1,47 @@
+package random
+
+import "math"
+
+// SubsetBernoulli returns a random subset of the integers 0 to n-1 (inclusive), in increasing order, where each
+// integer is included independently with probability p. Unlike SampleK(), the size of the subset is random,
+// with a binomial distribution with mean n*p. n must be non-negative, and p must be in the range 0.0 to 1.0
+// (inclusive). If p is exactly 0.0 or 1.0, no randomness is used.
+//
+// Instead of flipping a coin for each integer, this draws the gap between consecutive included integers, which
+// is geometrically distributed with success probability p, using inverse transform sampling (as in
+// Reservoir). This takes one draw per included integer, plus one, so it's much faster than n calls to BoolP()
+// when p is small.
+func SubsetBernoulli(src Source, n int, p float64) []int {
+	if n < 0 {
+		panic("n must be non-negative in call to SubsetBernoulli")
+	}
+	if !(p >= 0 && p <= 1) {
+		panic("p must be in [0, 1] in call to SubsetBernoulli")
+	}
+
+	switch p {
+	case 0:
+		return []int{}
+	case 1:
+		out := make([]int, n)
+		for i := range out {
+			out[i] = i
+		}
+		return out
+	}
+
+	out := []int{}
+	logQ := math.Log1p(-p)
+	// i is the last included integer, or -1 if there isn't one yet.
+	for i := -1; ; {
+		// s is the number of integers to skip before the next included one, which is at i+s+1.
+		s := math.Floor(math.Log(openFloat64(src)) / logQ)
+		// This also avoids overflowing if s is too large to fit in an int.
+		if s >= float64(n-1-i) {
+			break
+		}
+		i += int(s) + 1
+		out = append(out, i)
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubsetBernoulliInclusion checks that SubsetBernoulli() returns integers in range in increasing order,
+// that each integer is included with probability p, and that the total size of the subsets is as expected.
+func TestSubsetBernoulliInclusion(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	const n = 20
+	for _, p := range []float64{0.01, 0.3, 0.5, 0.9} {
+		src := rand.NewSource(int64(p * 100))
+		counts := make([]int, n)
+		total := 0
+		for trial := 0; trial < trials; trial++ {
+			s := SubsetBernoulli(src, n, p)
+			total += len(s)
+			for i, v := range s {
+				require.True(t, v >= 0 && v < n, "p=%f v=%d", p, v)
+				if i > 0 {
+					require.Less(t, s[i-1], v, "p=%f", p)
+				}
+				counts[v]++
+			}
+		}
+		requireBinomialCount(t, trials*n, p, total, "p=%f", p)
+		for v, count := range counts {
+			requireBinomialCount(t, trials, p, count, "p=%f v=%d", p, v)
+		}
+	}
+}
+
+// TestSubsetBernoulliLargeN checks that the size of a subset of a large range is close to n*p.
+func TestSubsetBernoulliLargeN(t *testing.T) {
+	t.Parallel()
+	const n = 10000000
+	const p = 0.001
+	s := SubsetBernoulli(rand.NewSource(1), n, p)
+	requireBinomialCount(t, n, p, len(s))
+	require.Less(t, s[len(s)-1], n)
+}
+
+// TestSubsetBernoulliEdgeCases checks that SubsetBernoulli() doesn't use any randomness for p == 0 or 1.
+func TestSubsetBernoulliEdgeCases(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, []int{}, SubsetBernoulli(&src, 5, 0))
+	require.Equal(t, []int{0, 1, 2, 3, 4}, SubsetBernoulli(&src, 5, 1))
+	require.Equal(t, []int{}, SubsetBernoulli(&src, 0, 1))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestSubsetBernoulliInvalid checks that SubsetBernoulli() panics for negative n or p outside [0, 1].
+func TestSubsetBernoulliInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "n must be non-negative in call to SubsetBernoulli", func() {
+		SubsetBernoulli(&src, -1, 0.5)
+	})
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		require.PanicsWithValue(t, "p must be in [0, 1] in call to SubsetBernoulli", func() {
+			SubsetBernoulli(&src, 5, p)
+		}, "p=%f", p)
+	}
+}
@@ -0,0 +1,39 @@
+package random
+
+// A TeeSource wraps a Source and records every value returned by Int63(), so that the sequence can later be
+// replayed with Rewind(). This is useful for differential testing: run an algorithm once, call Rewind(), and
+// then run another algorithm (or the same one again) with exactly the same randomness.
+//
+// After the recorded values have been replayed, a TeeSource continues with fresh values from the wrapped
+// Source, which are also recorded, so a later call to Rewind() replays everything returned so far.
+//
+// Like a StatsSource, a TeeSource only implements Source, even if the wrapped Source also implements Source64,
+// so that every draw goes through Int63(). Its memory use grows with the number of values drawn.
+type TeeSource struct {
+	src      Source
+	recorded []int64
+	// pos is the index in recorded of the next value to return. If pos == len(recorded), the next value is
+	// drawn from src.
+	pos int
+}
+
+// NewTeeSource returns a new TeeSource wrapping src.
+func NewTeeSource(src Source) *TeeSource {
+	return &TeeSource{src: src}
+}
+
+// Int63 returns the next recorded value if t is replaying, or else the result of Int63() on the wrapped Source,
+// which is then recorded.
+func (t *TeeSource) Int63() int64 {
+	if t.pos == len(t.recorded) {
+		t.recorded = append(t.recorded, t.src.Int63())
+	}
+	v := t.recorded[t.pos]
+	t.pos++
+	return v
+}
+
+// Rewind makes the next calls to Int63() replay all the values returned so far, from the beginning.
+func (t *TeeSource) Rewind() {
+	t.pos = 0
+}
@@ -0,0 +1,44 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTeeSourceRewind checks that after Rewind(), a TeeSource replays the recorded values exactly and then
+// continues with fresh values from the wrapped Source.
+func TestTeeSourceRewind(t *testing.T) {
+	t.Parallel()
+	expectedSrc := rand.NewSource(1)
+	var expected []int64
+	for i := 0; i < 20; i++ {
+		expected = append(expected, expectedSrc.Int63())
+	}
+
+	src := NewTeeSource(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expected[i], src.Int63(), "i=%d", i)
+	}
+	src.Rewind()
+	for i := 0; i < 20; i++ {
+		require.Equal(t, expected[i], src.Int63(), "i=%d", i)
+	}
+
+	// Rewinding again should replay the fresh values too.
+	src.Rewind()
+	for i := 0; i < 20; i++ {
+		require.Equal(t, expected[i], src.Int63(), "i=%d", i)
+	}
+}
+
+// TestTeeSourceReplaysAlgorithm checks that running a function that uses a variable number of draws again
+// after Rewind() gives the same result.
+func TestTeeSourceReplaysAlgorithm(t *testing.T) {
+	t.Parallel()
+	src := NewTeeSource(rand.NewSource(2))
+	expected := SampleK(src, 1000, 50)
+	src.Rewind()
+	require.Equal(t, expected, SampleK(src, 1000, 50))
+}
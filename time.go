@@ -0,0 +1,47 @@
+package random
+
+import (
+	"math"
+	"time"
+)
+
+// RandomTime returns a uniformly-distributed time in the range start to end (exclusive), to the nanosecond.
+// end must be after start, and end.Sub(start) must fit in a time.Duration, i.e. the two must be at most about
+// 292 years apart.
+//
+// The offset from start is drawn with Uint64n(), so it's exactly uniform over the nanoseconds in the range. The
+// result has the same location as start, and, like start.Add(), keeps start's monotonic clock reading, if any.
+func RandomTime(src Source, start, end time.Time) time.Time {
+	if !end.After(start) {
+		panic("end must be after start in call to RandomTime")
+	}
+
+	d := end.Sub(start)
+	// Sub() saturates at the largest time.Duration, so check that it didn't.
+	if d == math.MaxInt64 && !start.Add(d).Equal(end) {
+		panic("end-start must fit in a time.Duration in call to RandomTime")
+	}
+	return start.Add(time.Duration(Uint64n(src, uint64(d))))
+}
+
+// RandomDuration returns a uniformly-distributed duration in the range min to max (inclusive), to the
+// nanosecond. min must be at most max; any such pair is allowed, even the full range of time.Duration.
+//
+// Like RandomTime(), the offset from min is drawn with Uint64n(), so it's exactly uniform. If min == max, no
+// randomness is used.
+func RandomDuration(src Source, min, max time.Duration) time.Duration {
+	if min > max {
+		panic("min must be at most max in call to RandomDuration")
+	}
+
+	// max-min may overflow a time.Duration, but it always fits in a uint64.
+	span := uint64(max) - uint64(min)
+	switch span {
+	case 0:
+		return min
+	case math.MaxUint64:
+		// span+1 overflows, but every uint64 offset is in range.
+		return time.Duration(uint64(min) + randUint64(src))
+	}
+	return time.Duration(uint64(min) + Uint64n(src, span+1))
+}
@@ -0,0 +1,121 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomTimeUniform checks that RandomTime() returns times in [start, end) whose offsets from start are
+// roughly uniform across the span.
+func TestRandomTimeUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const buckets = 10
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(buckets * time.Second)
+	src := rand.NewSource(1)
+	var counts [buckets]int
+	for i := 0; i < trials; i++ {
+		tm := RandomTime(src, start, end)
+		require.False(t, tm.Before(start), "tm=%v", tm)
+		require.True(t, tm.Before(end), "tm=%v", tm)
+		require.Equal(t, time.UTC, tm.Location())
+		counts[tm.Sub(start)/time.Second]++
+	}
+	for b, count := range counts {
+		requireBinomialCount(t, trials, 1.0/buckets, count, "b=%d", b)
+	}
+}
+
+// TestRandomTimeSmallAndLargeSpans checks RandomTime() for a span of a single nanosecond, where it must return
+// start, and for the largest span that fits in a time.Duration.
+func TestRandomTimeSmallAndLargeSpans(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(2)
+	start := time.Unix(0, 0)
+	require.Equal(t, start, RandomTime(src, start, start.Add(1)))
+
+	end := start.Add(math.MaxInt64)
+	for i := 0; i < 100; i++ {
+		tm := RandomTime(src, start, end)
+		require.True(t, !tm.Before(start) && tm.Before(end), "tm=%v", tm)
+	}
+}
+
+// TestRandomTimeInvalid checks that RandomTime() panics if end isn't after start, or if the span doesn't fit in
+// a time.Duration.
+func TestRandomTimeInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	start := time.Unix(0, 0)
+	require.PanicsWithValue(t, "end must be after start in call to RandomTime", func() {
+		RandomTime(&src, start, start)
+	})
+	require.PanicsWithValue(t, "end must be after start in call to RandomTime", func() {
+		RandomTime(&src, start, start.Add(-1))
+	})
+	require.PanicsWithValue(t, "end-start must fit in a time.Duration in call to RandomTime", func() {
+		RandomTime(&src, start, start.Add(math.MaxInt64).Add(1))
+	})
+}
+
+// TestRandomDurationUniform checks that RandomDuration() returns durations in [min, max] roughly uniformly,
+// including both endpoints.
+func TestRandomDurationUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	const min = -2 * time.Nanosecond
+	const max = 3 * time.Nanosecond
+	src := rand.NewSource(3)
+	counts := make(map[time.Duration]int)
+	for i := 0; i < trials; i++ {
+		counts[RandomDuration(src, min, max)]++
+	}
+	require.Equal(t, 6, len(counts))
+	for d := min; d <= max; d++ {
+		requireBinomialCount(t, trials, 1.0/6, counts[d], "d=%v", d)
+	}
+}
+
+// TestRandomDurationEqual checks that RandomDuration() returns min without using any randomness if min == max.
+func TestRandomDurationEqual(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, d := range []time.Duration{math.MinInt64, -1, 0, time.Second, math.MaxInt64} {
+		require.Equal(t, d, RandomDuration(&src, d, d), "d=%v", d)
+	}
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestRandomDurationFullRange checks RandomDuration() for ranges that span more than half of the possible
+// durations, so that max-min overflows a time.Duration.
+func TestRandomDurationFullRange(t *testing.T) {
+	t.Parallel()
+	src := &testUint64Source{vs: []uint64{0, 1<<64 - 1, 1 << 63}}
+	require.Equal(t, time.Duration(math.MinInt64), RandomDuration(src, math.MinInt64, math.MaxInt64))
+	require.Equal(t, time.Duration(math.MaxInt64), RandomDuration(src, math.MinInt64, math.MaxInt64))
+	require.Equal(t, time.Duration(0), RandomDuration(src, math.MinInt64, math.MaxInt64))
+
+	rsrc := rand.NewSource(4)
+	negative := 0
+	for i := 0; i < 1000; i++ {
+		d := RandomDuration(rsrc, -time.Duration(math.MaxInt64), math.MaxInt64)
+		if d < 0 {
+			negative++
+		}
+	}
+	requireBinomialCount(t, 1000, 0.5, negative)
+}
+
+// TestRandomDurationInvalid checks that RandomDuration() panics if min > max.
+func TestRandomDurationInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "min must be at most max in call to RandomDuration", func() {
+		RandomDuration(&src, time.Second, time.Second-1)
+	})
+}
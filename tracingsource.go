@@ -0,0 +1,42 @@
+package random
+
+import (
+	"fmt"
+	"io"
+)
+
+// A TracingSource wraps a Source and writes each value returned by Int63() to an io.Writer, as 16 hex digits
+// followed by a newline, before returning it. This is useful for debugging flaky tests: the trace shows exactly
+// what randomness was used, and the values can be fed back through a stub Source to reproduce a failure.
+//
+// If the io.Writer is nil, a TracingSource just passes through the wrapped Source's values. Write errors don't
+// affect the returned values; the first one is saved and returned by Err(), and nothing more is written after
+// it.
+//
+// Like a StatsSource, a TracingSource only implements Source, even if the wrapped Source also implements
+// Source64, so that every draw goes through Int63() and is traced.
+type TracingSource struct {
+	src Source
+	w   io.Writer
+	err error
+}
+
+// NewTracingSource returns a new TracingSource wrapping src, which writes its trace to w, or doesn't trace
+// anything if w is nil.
+func NewTracingSource(src Source, w io.Writer) *TracingSource {
+	return &TracingSource{src: src, w: w}
+}
+
+// Int63 returns the result of Int63() on the wrapped Source, after writing it to the trace.
+func (t *TracingSource) Int63() int64 {
+	v := t.src.Int63()
+	if t.w != nil && t.err == nil {
+		_, t.err = fmt.Fprintf(t.w, "%016x\n", v)
+	}
+	return v
+}
+
+// Err returns the first error returned by the io.Writer, or nil if there wasn't one.
+func (t *TracingSource) Err() error {
+	return t.err
+}
@@ -0,0 +1,89 @@
+package random
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTracingSource checks that a TracingSource writes exactly the values it returns, in order, one per line in
+// hex.
+func TestTracingSource(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	src := NewTracingSource(rand.NewSource(1), &buf)
+	expectedSrc := rand.NewSource(1)
+	var returned []int64
+	for i := 0; i < 10; i++ {
+		v := src.Int63()
+		require.Equal(t, expectedSrc.Int63(), v, "i=%d", i)
+		returned = append(returned, v)
+	}
+	// Functions that draw through the TracingSource are traced too.
+	Uint32n(src, 1000)
+	returned = append(returned, expectedSrc.Int63())
+	require.NoError(t, src.Err())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Equal(t, len(returned), len(lines))
+	for i, line := range lines {
+		require.Equal(t, 16, len(line), "i=%d", i)
+		v, err := strconv.ParseInt(line, 16, 64)
+		require.NoError(t, err, "i=%d", i)
+		require.Equal(t, returned[i], v, "i=%d", i)
+	}
+
+	buf.Reset()
+	src = NewTracingSource(&int63Source{vs: []int64{0, 1<<63 - 1}}, &buf)
+	src.Int63()
+	src.Int63()
+	require.Equal(t, "0000000000000000\n7fffffffffffffff\n", buf.String())
+}
+
+// TestTracingSourceNilWriter checks that a TracingSource with a nil io.Writer passes through the wrapped
+// Source's values without allocating.
+func TestTracingSourceNilWriter(t *testing.T) {
+	src := NewTracingSource(rand.NewSource(1), nil)
+	expectedSrc := rand.NewSource(1)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expectedSrc.Int63(), src.Int63(), "i=%d", i)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		src.Int63()
+	})
+	require.Equal(t, 0.0, allocs)
+	require.NoError(t, src.Err())
+}
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct {
+	calls int
+}
+
+// errWrite is the error returned by errWriter.
+var errWrite = errors.New("write failed")
+
+// Write always returns errWrite.
+func (w *errWriter) Write(p []byte) (int, error) {
+	w.calls++
+	return 0, errWrite
+}
+
+// TestTracingSourceWriteError checks that a TracingSource keeps returning values after a write error, saves
+// the error, and stops writing.
+func TestTracingSourceWriteError(t *testing.T) {
+	t.Parallel()
+	w := &errWriter{}
+	src := NewTracingSource(rand.NewSource(1), w)
+	expectedSrc := rand.NewSource(1)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, expectedSrc.Int63(), src.Int63(), "i=%d", i)
+	}
+	require.Equal(t, errWrite, src.Err())
+	require.Equal(t, 1, w.calls)
+}
@@ -0,0 +1,63 @@
+package random
+
+import "math"
+
+// RandomTree returns the n-1 edges of a uniformly-distributed labeled tree on the vertices 0 to n-1
+// (inclusive), i.e. each of the n^(n-2) such trees is equally likely. Each edge is returned as [2]int{i, j}
+// with i < j, and the edges are in no particular order. n must be positive and fit in a uint32.
+//
+// This draws a random Prüfer sequence, i.e. n-2 values from Uint32n(src, n), and decodes it to a tree in O(n)
+// time. Since the Prüfer sequences are in one-to-one correspondence with the labeled trees, the tree is
+// uniformly distributed. For n <= 2, there's only one tree, so no randomness is used.
+func RandomTree(src Source, n int) [][2]int {
+	if n <= 0 || uint64(n) > math.MaxUint32 {
+		panic("n must be positive and fit in a uint32 in call to RandomTree")
+	}
+
+	if n == 1 {
+		return [][2]int{}
+	}
+
+	seq := make([]int, n-2)
+	// degree[v] is the number of edges incident to v that haven't been added yet.
+	degree := make([]int, n)
+	for v := range degree {
+		degree[v] = 1
+	}
+	for i := range seq {
+		seq[i] = int(Uint32n(src, uint32(n)))
+		degree[seq[i]]++
+	}
+
+	edge := func(u, v int) [2]int {
+		if u > v {
+			u, v = v, u
+		}
+		return [2]int{u, v}
+	}
+
+	// Each step of the decoding connects the smallest remaining leaf to the next vertex in seq, and then
+	// removes the leaf. Instead of searching for the smallest leaf each time, ptr only ever moves forward,
+	// since a vertex can only become a new leaf when its last occurrence in seq is consumed, in which case
+	// it's the next leaf to use if it's smaller than ptr.
+	out := make([][2]int, 0, n-1)
+	ptr := 0
+	for degree[ptr] != 1 {
+		ptr++
+	}
+	leaf := ptr
+	for _, v := range seq {
+		out = append(out, edge(leaf, v))
+		degree[v]--
+		if degree[v] == 1 && v < ptr {
+			leaf = v
+		} else {
+			ptr++
+			for degree[ptr] != 1 {
+				ptr++
+			}
+			leaf = ptr
+		}
+	}
+	return append(out, edge(leaf, n-1))
+}
@@ -0,0 +1,95 @@
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireTree checks that edges are the edges of a tree on the vertices 0 to n-1 (inclusive), i.e. that there
+// are n-1 of them and that they don't form a cycle, which together imply that the graph is connected.
+func requireTree(t *testing.T, n int, edges [][2]int, msgAndArgs ...interface{}) {
+	require.Equal(t, n-1, len(edges), msgAndArgs...)
+	// parent is a union-find forest of the vertices.
+	parent := make([]int, n)
+	for v := range parent {
+		parent[v] = v
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			v = parent[v]
+		}
+		return v
+	}
+	for _, e := range edges {
+		require.True(t, e[0] >= 0 && e[0] < e[1] && e[1] < n, msgAndArgs...)
+		a, b := find(e[0]), find(e[1])
+		require.NotEqual(t, a, b, msgAndArgs...)
+		parent[a] = b
+	}
+}
+
+// TestRandomTreeValid checks that RandomTree() always returns a tree.
+func TestRandomTreeValid(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for n := 1; n <= 20; n++ {
+		for i := 0; i < 100; i++ {
+			requireTree(t, n, RandomTree(src, n), "n=%d i=%d", n, i)
+		}
+	}
+	requireTree(t, 10000, RandomTree(src, 10000))
+}
+
+// TestRandomTreeUniform checks that RandomTree() returns each of the n^(n-2) labeled trees roughly uniformly
+// for small n.
+func TestRandomTreeUniform(t *testing.T) {
+	t.Parallel()
+	const trials = 100000
+	testCases := []struct {
+		n        int
+		numTrees int
+	}{
+		{3, 3},
+		{4, 16},
+		{5, 125},
+	}
+	for _, tc := range testCases {
+		src := rand.NewSource(int64(tc.n))
+		counts := make(map[string]int)
+		for i := 0; i < trials; i++ {
+			edges := RandomTree(src, tc.n)
+			sort.Slice(edges, func(i, j int) bool {
+				return edges[i][0] < edges[j][0] || (edges[i][0] == edges[j][0] && edges[i][1] < edges[j][1])
+			})
+			counts[fmt.Sprint(edges)]++
+		}
+		require.Equal(t, tc.numTrees, len(counts), "n=%d", tc.n)
+		for tree, count := range counts {
+			requireBinomialCount(t, trials, 1/float64(tc.numTrees), count, "n=%d tree=%s", tc.n, tree)
+		}
+	}
+}
+
+// TestRandomTreeSmall checks that RandomTree() doesn't use any randomness for n <= 2.
+func TestRandomTreeSmall(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.Equal(t, [][2]int{}, RandomTree(&src, 1))
+	require.Equal(t, [][2]int{{0, 1}}, RandomTree(&src, 2))
+	require.Equal(t, 0, src.callCount)
+}
+
+// TestRandomTreeInvalid checks that RandomTree() panics for non-positive n.
+func TestRandomTreeInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, n := range []int{0, -1} {
+		require.PanicsWithValue(t, "n must be positive and fit in a uint32 in call to RandomTree", func() {
+			RandomTree(&src, n)
+		}, "n=%d", n)
+	}
+}
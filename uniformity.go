@@ -0,0 +1,166 @@
+package random
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotUniform is wrapped by the error returned by TestSourceUniformity() when the test fails.
+var ErrNotUniform = errors.New("source failed uniformity test")
+
+// DefaultUniformityThreshold is the p-value below which TestSourceUniformity() fails by default. A correct
+// Source fails with this probability, so it's small enough that a test suite that calls
+// TestSourceUniformity() won't be flaky in practice, but large enough to catch grossly-biased Sources.
+const DefaultUniformityThreshold = 1e-4
+
+// maxUniformityN is the largest n that TestSourceUniformity() accepts, so that there's a bucket for each value
+// even on platforms where int is 32 bits.
+const maxUniformityN = math.MaxInt32
+
+// A UniformityOption configures a call to TestSourceUniformity().
+type UniformityOption func(*uniformityConfig)
+
+type uniformityConfig struct {
+	threshold float64
+}
+
+// WithUniformityThreshold returns a UniformityOption that makes TestSourceUniformity() fail if the p-value is
+// below threshold instead of DefaultUniformityThreshold.
+func WithUniformityThreshold(threshold float64) UniformityOption {
+	return func(c *uniformityConfig) {
+		c.threshold = threshold
+	}
+}
+
+// TestSourceUniformity draws the given number of values from Uint32n(src, n) and performs a chi-squared
+// goodness-of-fit test against the uniform distribution. If the p-value is below the threshold (which is
+// DefaultUniformityThreshold unless overridden by WithUniformityThreshold()), it returns an error wrapping
+// ErrNotUniform; otherwise, it returns nil. n must be in the range 2 to 2³¹-1 (inclusive), since it keeps a
+// count for each value, and draws must be at least 5*n, so that each expected count is large enough for the
+// chi-squared approximation to be accurate.
+//
+// This is meant to be called from the test suite of a custom Source implementation, as a quick sanity check
+// that it works with Uint32n() and the functions built on it. It isn't a substitute for a real statistical
+// test suite like TestU01 or PractRand.
+func TestSourceUniformity(src Source, n uint32, draws int, opts ...UniformityOption) error {
+	if n < 2 || n > maxUniformityN {
+		panic("n must be in [2, 2³¹-1] in call to TestSourceUniformity")
+	}
+	if draws < 0 || uint64(draws) < 5*uint64(n) {
+		panic("draws must be at least 5*n in call to TestSourceUniformity")
+	}
+
+	config := uniformityConfig{threshold: DefaultUniformityThreshold}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	counts := CollectHistogram(func() int {
+		return int(Uint32n(src, n))
+	}, int(n), draws)
+
+	expected := float64(draws) / float64(n)
+	var chiSquared float64
+	for _, count := range counts {
+		d := float64(count) - expected
+		chiSquared += d * d / expected
+	}
+
+	p := chiSquaredSurvival(chiSquared, int(n)-1)
+	if p < config.threshold {
+		return fmt.Errorf("%w: chi-squared statistic %g with %d degrees of freedom has p-value %g < %g",
+			ErrNotUniform, chiSquared, n-1, p, config.threshold)
+	}
+	return nil
+}
+
+// CollectHistogram calls draw the given number of times and returns the number of times it returned each value
+// in the range 0 to buckets-1 (inclusive). Values less than 0 are counted in the first bucket, and values greater
+// than buckets-1 are counted in the last bucket, so callers that care about out-of-range values should make
+// sure they land in buckets of their own. buckets must be positive, and samples must be non-negative.
+//
+// This is meant to be used to check the shape of a custom distribution built on this package, e.g. by
+// comparing the counts to the expected ones with a chi-squared test, as TestSourceUniformity() does.
+func CollectHistogram(draw func() int, buckets, samples int) []int {
+	if buckets <= 0 {
+		panic("buckets must be positive in call to CollectHistogram")
+	}
+	if samples < 0 {
+		panic("samples must be non-negative in call to CollectHistogram")
+	}
+
+	counts := make([]int, buckets)
+	for i := 0; i < samples; i++ {
+		v := draw()
+		if v < 0 {
+			v = 0
+		} else if v >= buckets {
+			v = buckets - 1
+		}
+		counts[v]++
+	}
+	return counts
+}
+
+// chiSquaredSurvival returns the probability that a chi-squared-distributed value with dof degrees of freedom
+// is at least x.
+func chiSquaredSurvival(x float64, dof int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return regularizedGammaQ(float64(dof)/2, x/2)
+}
+
+// regularizedGammaQ returns the regularized upper incomplete gamma function Q(a, x) = Γ(a, x)/Γ(a), for a > 0
+// and x > 0, using the series expansion for x < a+1 and the continued fraction otherwise (see section 6.2 of
+// Numerical Recipes).
+func regularizedGammaQ(a, x float64) float64 {
+	const eps = 1e-15
+	const tiny = 1e-300
+	const maxIterations = 1000000
+
+	lgammaA, _ := math.Lgamma(a)
+	logPrefactor := -x + a*math.Log(x) - lgammaA
+
+	if x < a+1 {
+		// Compute P(a, x) by its series, and return 1 - P(a, x).
+		ap := a
+		sum := 1 / a
+		del := sum
+		for i := 0; i < maxIterations; i++ {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(logPrefactor)
+	}
+
+	// Compute Q(a, x) by its continued fraction, using the modified Lentz's method.
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < maxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return math.Exp(logPrefactor) * h
+}
@@ -0,0 +1,116 @@
+package random
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestChiSquaredSurvival checks chiSquaredSurvival() against known values.
+func TestChiSquaredSurvival(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, 1.0, chiSquaredSurvival(0, 5))
+	// For 2 degrees of freedom, the survival function is exp(-x/2).
+	for _, x := range []float64{0.5, 1, 2, 10, 50} {
+		require.InEpsilon(t, math.Exp(-x/2), chiSquaredSurvival(x, 2), 1e-12, "x=%v", x)
+	}
+	// For 1 degree of freedom, the survival function is erfc(sqrt(x/2)).
+	for _, x := range []float64{0.5, 1, 2, 10, 50} {
+		require.InEpsilon(t, math.Erfc(math.Sqrt(x/2)), chiSquaredSurvival(x, 1), 1e-10, "x=%v", x)
+	}
+	// Critical values from standard tables.
+	require.InDelta(t, 0.05, chiSquaredSurvival(16.919, 9), 1e-4)
+	require.InDelta(t, 0.01, chiSquaredSurvival(135.807, 100), 1e-4)
+}
+
+// TestSourceUniformityPasses checks that TestSourceUniformity() returns nil for a good Source.
+func TestSourceUniformityPasses(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint32{2, 10, 1000} {
+		require.NoError(t, TestSourceUniformity(rand.NewSource(1), n, 100000), "n=%d", n)
+	}
+}
+
+// upperHalfSource is a Source that only returns values with the top bit set, so Uint32n() only returns values
+// in the upper half of its range.
+type upperHalfSource struct {
+	src Source
+}
+
+func (s upperHalfSource) Int63() int64 {
+	return s.src.Int63() | 1<<62
+}
+
+// TestSourceUniformityFails checks that TestSourceUniformity() returns an error wrapping ErrNotUniform for a
+// biased Source.
+func TestSourceUniformityFails(t *testing.T) {
+	t.Parallel()
+	err := TestSourceUniformity(upperHalfSource{rand.NewSource(1)}, 10, 1000)
+	require.True(t, errors.Is(err, ErrNotUniform), "err=%v", err)
+}
+
+// TestSourceUniformityThreshold checks that WithUniformityThreshold() overrides the default threshold.
+func TestSourceUniformityThreshold(t *testing.T) {
+	t.Parallel()
+	// A threshold of 0 always passes, and a threshold above 1 always fails.
+	require.NoError(t, TestSourceUniformity(upperHalfSource{rand.NewSource(1)}, 10, 1000,
+		WithUniformityThreshold(0)))
+	err := TestSourceUniformity(rand.NewSource(1), 10, 1000, WithUniformityThreshold(1.1))
+	require.True(t, errors.Is(err, ErrNotUniform), "err=%v", err)
+}
+
+// TestSourceUniformityInvalid checks that TestSourceUniformity() panics for invalid arguments.
+func TestSourceUniformityInvalid(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	for _, n := range []uint32{0, 1, 1 << 31, 0xffffffff} {
+		require.PanicsWithValue(t, "n must be in [2, 2³¹-1] in call to TestSourceUniformity", func() {
+			_ = TestSourceUniformity(&src, n, 100)
+		}, "n=%d", n)
+	}
+	require.PanicsWithValue(t, "draws must be at least 5*n in call to TestSourceUniformity", func() {
+		_ = TestSourceUniformity(&src, 10, 49)
+	})
+	require.PanicsWithValue(t, "draws must be at least 5*n in call to TestSourceUniformity", func() {
+		_ = TestSourceUniformity(&src, 10, -1)
+	})
+	// 5*n doesn't fit in 32 bits here, so this checks that the comparison doesn't overflow.
+	require.PanicsWithValue(t, "draws must be at least 5*n in call to TestSourceUniformity", func() {
+		_ = TestSourceUniformity(&src, 1<<31-1, math.MaxInt32)
+	})
+}
+
+// TestCollectHistogram checks that CollectHistogram() tallies a deterministic draw function correctly, and
+// clamps out-of-range values into the first and last buckets.
+func TestCollectHistogram(t *testing.T) {
+	t.Parallel()
+	vs := []int{0, 1, 1, 2, 2, 2, -1, 3, 100}
+	i := 0
+	draw := func() int {
+		v := vs[i]
+		i++
+		return v
+	}
+	require.Equal(t, []int{2, 2, 5}, CollectHistogram(draw, 3, len(vs)))
+	require.Equal(t, len(vs), i)
+
+	require.Equal(t, []int{0, 0}, CollectHistogram(func() int {
+		require.Fail(t, "draw called")
+		return 0
+	}, 2, 0))
+}
+
+// TestCollectHistogramInvalid checks that CollectHistogram() panics for non-positive buckets or negative samples.
+func TestCollectHistogramInvalid(t *testing.T) {
+	t.Parallel()
+	draw := func() int { return 0 }
+	require.PanicsWithValue(t, "buckets must be positive in call to CollectHistogram", func() {
+		CollectHistogram(draw, 0, 1)
+	})
+	require.PanicsWithValue(t, "samples must be non-negative in call to CollectHistogram", func() {
+		CollectHistogram(draw, 1, -1)
+	})
+}
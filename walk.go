@@ -0,0 +1,21 @@
+package random
+
+// RandomWalk returns the positions of a simple symmetric random walk on the integers that starts at 0 and
+// takes the given number of ±1 steps, using one call to Bool() per step. The returned slice has length
+// steps+1, with the starting position 0 at index 0 and the final position at index steps. steps must be
+// non-negative.
+func RandomWalk(src Source, steps int) []int {
+	if steps < 0 {
+		panic("steps must be non-negative in call to RandomWalk")
+	}
+
+	positions := make([]int, steps+1)
+	for i := 1; i <= steps; i++ {
+		if Bool(src) {
+			positions[i] = positions[i-1] + 1
+		} else {
+			positions[i] = positions[i-1] - 1
+		}
+	}
+	return positions
+}
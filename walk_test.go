@@ -0,0 +1,49 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomWalkSteps checks that RandomWalk() starts at 0 and takes steps of ±1.
+func TestRandomWalkSteps(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for steps := 0; steps < 100; steps++ {
+		positions := RandomWalk(src, steps)
+		require.Equal(t, steps+1, len(positions))
+		require.Equal(t, 0, positions[0])
+		for i := 1; i < len(positions); i++ {
+			d := positions[i] - positions[i-1]
+			require.True(t, d == 1 || d == -1, "steps=%d i=%d d=%d", steps, i, d)
+		}
+	}
+}
+
+// TestRandomWalkDisplacement checks that the sample mean and variance of the final position of RandomWalk()
+// are about 0 and steps.
+func TestRandomWalkDisplacement(t *testing.T) {
+	t.Parallel()
+	const trials = 20000
+	const steps = 100
+	src := rand.NewSource(2)
+	mean, variance := sampleMoments(trials, func() float64 {
+		return float64(RandomWalk(src, steps)[steps])
+	})
+	// The standard error of the mean is sqrt(steps/trials), and the standard error of the variance is about
+	// steps*sqrt(2/trials).
+	require.InDelta(t, 0, mean, 5*math.Sqrt(steps/float64(trials)))
+	require.InDelta(t, steps, variance, 5*steps*math.Sqrt(2.0/trials))
+}
+
+// TestRandomWalkNegative checks that RandomWalk() panics for negative steps.
+func TestRandomWalkNegative(t *testing.T) {
+	t.Parallel()
+	src := testSource{}
+	require.PanicsWithValue(t, "steps must be non-negative in call to RandomWalk", func() {
+		RandomWalk(&src, -1)
+	})
+}
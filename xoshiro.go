@@ -0,0 +1,134 @@
+package random
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// A Xoshiro256 is a Source64 that implements Blackman and Vigna's xoshiro256** generator (see
+// https://prng.di.unimi.it/ ). Like PCG, its output is completely specified, so it's identical across platforms
+// and Go versions. It has a period of 2²⁵⁶-1, and supports jumping ahead by 2¹²⁸ values.
+//
+// Each call to Uint64() returns one xoshiro256** output, and Int63() returns its top 63 bits.
+//
+// A Xoshiro256 is not safe for concurrent use by multiple goroutines.
+type Xoshiro256 struct {
+	s [4]uint64
+}
+
+// NewXoshiro256 returns a new Xoshiro256 whose state is filled in with the first four outputs of SplitMix64
+// seeded with seed, as recommended by the reference implementation.
+func NewXoshiro256(seed uint64) *Xoshiro256 {
+	x := &Xoshiro256{}
+	x.Reseed(seed)
+	return x
+}
+
+// Reseed resets x so that it returns the same values as a new Xoshiro256 returned by NewXoshiro256(seed)
+// would.
+func (x *Xoshiro256) Reseed(seed uint64) {
+	for i := range x.s {
+		x.s[i] = splitMix64(&seed)
+	}
+}
+
+// Uint64 returns the next xoshiro256** output, which is uniformly distributed in the range 0 to 2⁶⁴-1
+// (inclusive).
+func (x *Xoshiro256) Uint64() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// Int63 returns the top 63 bits of x.Uint64().
+func (x *Xoshiro256) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+// xoshiro256Jump holds the coefficients of the jump polynomial from the reference implementation.
+var xoshiro256Jump = [4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c}
+
+// Jump advances x as if 2¹²⁸ values had been generated, like jump() in the reference implementation.
+func (x *Xoshiro256) Jump() {
+	var s [4]uint64
+	for _, jump := range xoshiro256Jump {
+		for b := uint(0); b < 64; b++ {
+			if jump&(1<<b) != 0 {
+				for i := range s {
+					s[i] ^= x.s[i]
+				}
+			}
+			x.Uint64()
+		}
+	}
+	x.s = s
+}
+
+// Clone returns a new Xoshiro256 with the same state as x.
+func (x *Xoshiro256) Clone() Source {
+	clone := *x
+	return &clone
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the state of x compactly, so that it can be
+// saved and restored later, e.g. to resume a long-running simulation. The encoding starts with a version byte,
+// followed by the four words of the state as big-endian uint64s.
+func (x *Xoshiro256) MarshalBinary() ([]byte, error) {
+	return marshalState(x.s[:]...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring the state saved by MarshalBinary(), so that
+// x returns the same values that the encoded Xoshiro256 would have.
+func (x *Xoshiro256) UnmarshalBinary(data []byte) error {
+	var s [4]uint64
+	if err := unmarshalState(data, &s[0], &s[1], &s[2], &s[3]); err != nil {
+		return err
+	}
+	return x.setState(s)
+}
+
+// GobEncode implements gob.GobEncoder, using the same encoding as MarshalBinary().
+func (x *Xoshiro256) GobEncode() ([]byte, error) {
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, using the same encoding as UnmarshalBinary().
+func (x *Xoshiro256) GobDecode(data []byte) error {
+	return x.UnmarshalBinary(data)
+}
+
+// setState sets the state of x, which must not be all zero.
+func (x *Xoshiro256) setState(s [4]uint64) error {
+	if s == [4]uint64{} {
+		return fmt.Errorf("%w: Xoshiro256 state must not be all zero", ErrInvalidState)
+	}
+	x.s = s
+	return nil
+}
+
+// xoshiro256JSON is the JSON encoding of the state of a Xoshiro256.
+type xoshiro256JSON struct {
+	S [4]uint64 `json:"s"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the state of x as a JSON object.
+func (x *Xoshiro256) MarshalJSON() ([]byte, error) {
+	return json.Marshal(xoshiro256JSON{x.s})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring the state saved by MarshalJSON().
+func (x *Xoshiro256) UnmarshalJSON(data []byte) error {
+	var j xoshiro256JSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	return x.setState(j.S)
+}
@@ -0,0 +1,55 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestXoshiro256Reference checks Xoshiro256 against the reference outputs for the state {1, 2, 3, 4}.
+func TestXoshiro256Reference(t *testing.T) {
+	t.Parallel()
+	x := &Xoshiro256{s: [4]uint64{1, 2, 3, 4}}
+	var vs []uint64
+	for i := 0; i < 6; i++ {
+		vs = append(vs, x.Uint64())
+	}
+	require.Equal(t, []uint64{
+		11520, 0, 1509978240, 1215971899390074240, 1216172134540287360, 607988272756665600,
+	}, vs)
+}
+
+// TestXoshiro256Jump checks Xoshiro256.Jump() against the reference jump() for the state {1, 2, 3, 4}.
+func TestXoshiro256Jump(t *testing.T) {
+	t.Parallel()
+	x := &Xoshiro256{s: [4]uint64{1, 2, 3, 4}}
+	x.Jump()
+	require.Equal(t, [4]uint64{
+		0x8c7a153956b5f3d1, 0x701f1a713401d85e, 0x6527f66a65469085, 0x8386b786c4408050,
+	}, x.s)
+	var vs []uint64
+	for i := 0; i < 3; i++ {
+		vs = append(vs, x.Uint64())
+	}
+	require.Equal(t, []uint64{13534147089533256664, 7126240192422241655, 3805973808039778091}, vs)
+}
+
+// TestXoshiro256Seed checks that NewXoshiro256() fills in the state with SplitMix64 outputs.
+func TestXoshiro256Seed(t *testing.T) {
+	t.Parallel()
+	x := NewXoshiro256(1234567)
+	require.Equal(t, [4]uint64{
+		6457827717110365317, 3203168211198807973, 9817491932198370423, 4593380528125082431,
+	}, x.s)
+}
+
+// TestXoshiro256Clone checks that a clone of a Xoshiro256 returns the same values as the original, and that
+// advancing one doesn't affect the other.
+func TestXoshiro256Clone(t *testing.T) {
+	t.Parallel()
+	x := NewXoshiro256(1)
+	clone := x.Clone()
+	v := x.Uint64()
+	x.Uint64()
+	require.Equal(t, int64(v>>1), clone.Int63())
+}